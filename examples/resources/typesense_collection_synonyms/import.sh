@@ -0,0 +1 @@
+terraform import typesense_collection_synonyms.my_collection my-collection
@@ -0,0 +1,15 @@
+resource "typesense_collection_synonyms" "my_collection" {
+  collection_name = typesense_collection.my_collection.name
+
+  synonyms = {
+    "smart-phone" = {
+      root     = "smart phone"
+      synonyms = ["iphone", "android"]
+    }
+
+    "cpp" = {
+      synonyms         = ["c++", "cpp", "cplusplus"]
+      symbols_to_index = ["+"]
+    }
+  }
+}
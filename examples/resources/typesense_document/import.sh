@@ -1 +1,3 @@
-terraform import typesense_document.my_document document-id
+# <collection>/<document id>, both URL-escaped
+terraform import typesense_document.my_document my-collection/document-id
+terraform import typesense_document.my_user users/jane.doe@example.com
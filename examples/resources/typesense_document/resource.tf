@@ -9,3 +9,42 @@ resource "typesense_document" "my-document" {
 }
 EOF
 }
+
+resource "typesense_document" "shared-document" {
+  name            = "shared-document"
+  collection_name = typesense_collection.test_collection.name
+  action          = "emplace"
+  ownership       = "declared_keys"
+
+  remove_declared_keys_on_destroy = true
+
+  document = jsonencode({
+    is_featured = true
+  })
+}
+
+resource "typesense_document" "large-document" {
+  name            = "large-document"
+  collection_name = typesense_collection.test_collection.name
+  source_file     = "${path.module}/documents/large-document.json"
+}
+
+resource "typesense_document" "typed-document" {
+  name            = "typed-document"
+  collection_name = typesense_collection.test_collection.name
+
+  fields = {
+    title      = "Typed document"
+    price      = 12.5
+    product_id = 1234567890123456789
+    tags       = ["new", "featured"]
+  }
+}
+
+resource "typesense_document" "settings" {
+  name            = "settings"
+  collection_name = typesense_collection.test_collection.name
+  source_file     = "${path.module}/documents/settings.json"
+  state_storage   = "hash"
+  conflict_policy = "fail"
+}
@@ -0,0 +1,21 @@
+resource "typesense_documents" "my-documents" {
+  collection_name = typesense_collection.test_collection.name
+  action          = "upsert"
+  batch_size      = 100
+
+  documents = [
+    jsonencode({
+      id     = "1"
+      field1 = "testValue1"
+    }),
+    jsonencode({
+      id     = "2"
+      field1 = "testValue2"
+    }),
+  ]
+}
+
+resource "typesense_documents" "seed" {
+  collection_name = typesense_collection.test_collection.name
+  documents_file  = "${path.module}/seed/*.jsonl"
+}
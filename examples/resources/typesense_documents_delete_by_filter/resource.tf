@@ -0,0 +1,11 @@
+resource "typesense_documents_delete_by_filter" "offboard-tenant" {
+  collection_name = typesense_collection.test_collection.name
+  filter_by       = "tenant_id:=acme"
+  batch_size      = 500
+}
+
+resource "typesense_documents_delete_by_filter" "cleanup-fixtures" {
+  collection_name = typesense_collection.test_collection.name
+  filter_by       = "is_fixture:=true"
+  run_on          = "destroy"
+}
@@ -0,0 +1,8 @@
+resource "typesense_documents_patch" "unfeature-discontinued" {
+  collection_name = typesense_collection.test_collection.name
+  filter_by       = "discontinued:=true"
+
+  patch = jsonencode({
+    is_featured = false
+  })
+}
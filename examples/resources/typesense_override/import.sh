@@ -0,0 +1,2 @@
+# <collection>/<override name>, both URL-escaped
+terraform import typesense_override.apple my-collection/customize-apple
@@ -0,0 +1,56 @@
+resource "typesense_override" "apple" {
+  name            = "customize-apple"
+  collection_name = typesense_collection.my_collection.name
+
+  rule {
+    query = "apple"
+    match = "exact"
+  }
+
+  includes {
+    id       = "422"
+    position = 1
+  }
+
+  includes {
+    id       = "54"
+    position = 2
+  }
+
+  excludes {
+    id = "287"
+  }
+}
+
+resource "typesense_override" "brand_filter" {
+  name            = "brand-filter"
+  collection_name = typesense_collection.my_collection.name
+
+  rule {
+    query = "{brand} phone"
+    match = "contains"
+  }
+
+  filter_by             = "brand:={brand}"
+  sort_by               = "popularity:desc"
+  remove_matched_tokens = true
+  effective_from_ts     = 1735689600
+  effective_to_ts       = 1767225599
+
+  metadata = jsonencode({
+    banner = "phones"
+  })
+}
+
+resource "typesense_override" "sale" {
+  name            = "sale"
+  collection_name = typesense_collection.my_collection.name
+
+  rule {
+    tags = ["sale"]
+  }
+
+  filter_curated_hits = true
+  stop_processing     = false
+  replace_query       = "discount"
+}
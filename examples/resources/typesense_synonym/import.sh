@@ -1 +1,2 @@
-terraform import typesense_synonym.my_synonym my-synonym
+# <collection>/<synonym name>, both URL-escaped
+terraform import typesense_synonym.my_synonym my-collection/my-synonym
@@ -5,3 +5,19 @@ resource "typesense_synonym" "my_synonym" {
 
   synonyms = ["iphone", "android"]
 }
+
+resource "typesense_synonym" "cpp" {
+  name            = "cpp"
+  collection_name = typesense_collection.my_collection.name
+
+  synonyms         = ["c++", "cpp", "cplusplus"]
+  symbols_to_index = ["+"]
+}
+
+resource "typesense_synonym" "japanese" {
+  name            = "japanese-phone"
+  collection_name = typesense_collection.my_collection.name
+  locale          = "ja"
+
+  synonyms = ["携帯電話", "スマホ"]
+}
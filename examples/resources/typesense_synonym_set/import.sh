@@ -0,0 +1 @@
+terraform import typesense_synonym_set.products products
@@ -0,0 +1,25 @@
+resource "typesense_synonym_set" "products" {
+  name = "products"
+
+  items {
+    id       = "smart-phone"
+    root     = "smart phone"
+    synonyms = ["iphone", "android"]
+  }
+
+  items {
+    id               = "cpp"
+    synonyms         = ["c++", "cpp", "cplusplus"]
+    symbols_to_index = ["+"]
+  }
+}
+
+resource "typesense_collection" "products" {
+  name         = "products"
+  synonym_sets = [typesense_synonym_set.products.name]
+
+  fields {
+    name = "title"
+    type = "string"
+  }
+}
@@ -0,0 +1,16 @@
+resource "typesense_synonyms_file" "products" {
+  collection_name = typesense_collection.my_collection.name
+  source_file     = "${path.module}/synonyms.txt"
+}
+
+resource "typesense_synonyms_file" "brands" {
+  collection_name = typesense_collection.my_collection.name
+  id_prefix       = "brands"
+
+  content = <<-EOT
+    # multi-way synonyms
+    ipod, i-pod, i pod
+    # one-way synonyms
+    sea biscuit, sea biscit => seabiscuit
+  EOT
+}
@@ -0,0 +1,663 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/typesense/typesense-go/typesense"
+	"github.com/typesense/typesense-go/typesense/api"
+)
+
+// TypesenseClient is made available to resources during Configure. It embeds
+// the typed typesense client and keeps the generated API client around for
+// endpoints and parameters which the typed client does not expose. The bulk
+// clients have no request timeout and are used to export and import documents.
+type TypesenseClient struct {
+	*typesense.Client
+
+	api     *api.Client
+	bulk    *typesense.Client
+	bulkApi *api.Client
+
+	version      string
+	versionMutex sync.Mutex
+
+	collections     map[string]*collectionResponse
+	collectionMutex sync.Mutex
+}
+
+func NewTypesenseClient(apiClient *api.Client, bulkApiClient *api.Client) *TypesenseClient {
+	return &TypesenseClient{
+		Client:  typesense.NewClient(typesense.WithAPIClient(&api.ClientWithResponses{ClientInterface: apiClient})),
+		api:     apiClient,
+		bulk:    typesense.NewClient(typesense.WithAPIClient(&api.ClientWithResponses{ClientInterface: bulkApiClient})),
+		bulkApi: bulkApiClient,
+	}
+}
+
+// collectionSchema extends api.CollectionSchema with collection settings the
+// typed client does not know about.
+type collectionSchema struct {
+	api.CollectionSchema
+
+	VoiceQueryModel *voiceQueryModel `json:"voice_query_model,omitempty"`
+	SynonymSets     []string         `json:"synonym_sets,omitempty"`
+}
+
+// collectionResponse extends api.CollectionResponse with collection settings
+// the typed client does not know about.
+type collectionResponse struct {
+	api.CollectionResponse
+
+	VoiceQueryModel *voiceQueryModel `json:"voice_query_model,omitempty"`
+	SynonymSets     []string         `json:"synonym_sets,omitempty"`
+}
+
+type voiceQueryModel struct {
+	ModelName string `json:"model_name"`
+}
+
+// synonymSchema extends api.SearchSynonymSchema with synonym settings the
+// typed client does not know about.
+type synonymSchema struct {
+	api.SearchSynonymSchema
+
+	Locale         *string  `json:"locale,omitempty"`
+	SymbolsToIndex []string `json:"symbols_to_index,omitempty"`
+}
+
+// synonymResponse extends api.SearchSynonym with synonym settings the typed
+// client does not know about.
+type synonymResponse struct {
+	api.SearchSynonym
+
+	Locale         *string  `json:"locale,omitempty"`
+	SymbolsToIndex []string `json:"symbols_to_index,omitempty"`
+}
+
+// synonymSet is a standalone set of synonyms. Since Typesense v30 synonyms
+// are kept in sets which are linked to collections, instead of being stored
+// per collection.
+type synonymSet struct {
+	Name  string           `json:"name,omitempty"`
+	Items []synonymSetItem `json:"items"`
+}
+
+type synonymSetItem struct {
+	Id             string   `json:"id"`
+	Root           string   `json:"root,omitempty"`
+	Synonyms       []string `json:"synonyms"`
+	Locale         string   `json:"locale,omitempty"`
+	SymbolsToIndex []string `json:"symbols_to_index,omitempty"`
+}
+
+// overrideSchema is the full schema of a search override, most of its
+// settings are not known to the typed client. Metadata is kept as raw JSON.
+type overrideSchema struct {
+	Id                  string            `json:"id,omitempty"`
+	Rule                overrideRule      `json:"rule"`
+	Includes            []overrideInclude `json:"includes,omitempty"`
+	Excludes            []overrideExclude `json:"excludes,omitempty"`
+	FilterBy            string            `json:"filter_by,omitempty"`
+	SortBy              string            `json:"sort_by,omitempty"`
+	ReplaceQuery        string            `json:"replace_query,omitempty"`
+	RemoveMatchedTokens *bool             `json:"remove_matched_tokens,omitempty"`
+	FilterCuratedHits   *bool             `json:"filter_curated_hits,omitempty"`
+	EffectiveFromTs     *int64            `json:"effective_from_ts,omitempty"`
+	EffectiveToTs       *int64            `json:"effective_to_ts,omitempty"`
+	StopProcessing      *bool             `json:"stop_processing,omitempty"`
+	Metadata            json.RawMessage   `json:"metadata,omitempty"`
+}
+
+type overrideRule struct {
+	Query    string   `json:"query,omitempty"`
+	Match    string   `json:"match,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	FilterBy string   `json:"filter_by,omitempty"`
+}
+
+type overrideInclude struct {
+	Id       string `json:"id"`
+	Position int64  `json:"position"`
+}
+
+type overrideExclude struct {
+	Id string `json:"id"`
+}
+
+// createCollection creates a collection from the given schema. When
+// sourceName is not empty the collection is created as a clone of the schema
+// of the source collection instead.
+func (c *TypesenseClient) createCollection(ctx context.Context, schema interface{}, sourceName string) (*collectionResponse, error) {
+	body, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if sourceName != "" {
+		query.Set("src_name", sourceName)
+	}
+
+	response, err := c.api.CreateCollectionWithBody(ctx, "application/json", bytes.NewReader(body), withQuery(query))
+	if err != nil {
+		return nil, err
+	}
+
+	var collection collectionResponse
+
+	if err := decodeResponse(response, &collection); err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// retrieveCollection retrieves the schema of a collection.
+func (c *TypesenseClient) retrieveCollection(ctx context.Context, name string) (*collectionResponse, error) {
+	response, err := c.api.GetCollection(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection collectionResponse
+
+	if err := decodeResponse(response, &collection); err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// cachedCollection returns the collection like retrieveCollection, but
+// retrieves every collection only once, so that the documents of a collection
+// do not fetch its schema again and again while planning.
+func (c *TypesenseClient) cachedCollection(ctx context.Context, name string) (*collectionResponse, error) {
+	c.collectionMutex.Lock()
+	defer c.collectionMutex.Unlock()
+
+	if collection, ok := c.collections[name]; ok {
+		return collection, nil
+	}
+
+	collection, err := c.retrieveCollection(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.collections == nil {
+		c.collections = make(map[string]*collectionResponse)
+	}
+
+	c.collections[name] = collection
+
+	return collection, nil
+}
+
+// forgetCollection drops the cached collection after it was changed.
+func (c *TypesenseClient) forgetCollection(name string) {
+	c.collectionMutex.Lock()
+	defer c.collectionMutex.Unlock()
+
+	delete(c.collections, name)
+}
+
+// upsertSynonym creates or replaces a synonym of a collection.
+func (c *TypesenseClient) upsertSynonym(ctx context.Context, collectionName string, synonymId string, schema *synonymSchema) (*synonymResponse, error) {
+	body, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.api.UpsertSearchSynonymWithBody(ctx, collectionName, synonymId, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var synonym synonymResponse
+
+	if err := decodeResponse(response, &synonym); err != nil {
+		return nil, err
+	}
+
+	return &synonym, nil
+}
+
+// retrieveSynonym retrieves a synonym of a collection.
+func (c *TypesenseClient) retrieveSynonym(ctx context.Context, collectionName string, synonymId string) (*synonymResponse, error) {
+	response, err := c.api.GetSearchSynonym(ctx, collectionName, synonymId)
+	if err != nil {
+		return nil, err
+	}
+
+	var synonym synonymResponse
+
+	if err := decodeResponse(response, &synonym); err != nil {
+		return nil, err
+	}
+
+	return &synonym, nil
+}
+
+// upsertOverride creates or replaces a search override of a collection.
+func (c *TypesenseClient) upsertOverride(ctx context.Context, collectionName string, overrideId string, schema *overrideSchema) (*overrideSchema, error) {
+	body, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.api.UpsertSearchOverrideWithBody(ctx, collectionName, overrideId, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var override overrideSchema
+
+	if err := decodeResponse(response, &override); err != nil {
+		return nil, err
+	}
+
+	return &override, nil
+}
+
+// retrieveOverride retrieves a search override of a collection.
+func (c *TypesenseClient) retrieveOverride(ctx context.Context, collectionName string, overrideId string) (*overrideSchema, error) {
+	response, err := c.api.GetSearchOverride(ctx, collectionName, overrideId)
+	if err != nil {
+		return nil, err
+	}
+
+	var override overrideSchema
+
+	if err := decodeResponse(response, &override); err != nil {
+		return nil, err
+	}
+
+	return &override, nil
+}
+
+// listSynonyms retrieves every synonym of a collection.
+func (c *TypesenseClient) listSynonyms(ctx context.Context, collectionName string) ([]synonymResponse, error) {
+	response, err := c.api.GetSearchSynonyms(ctx, collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Synonyms []synonymResponse `json:"synonyms"`
+	}
+
+	if err := decodeResponse(response, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Synonyms, nil
+}
+
+// updateCollection patches the settings of a collection. Unlike the typed
+// client it accepts settings the typed client does not know about.
+func (c *TypesenseClient) updateCollection(ctx context.Context, name string, schema interface{}) error {
+	body, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.api.UpdateCollectionWithBody(ctx, name, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(response, nil)
+}
+
+// upsertSynonymSet creates or replaces a synonym set with the given items.
+func (c *TypesenseClient) upsertSynonymSet(ctx context.Context, name string, set *synonymSet) (*synonymSet, error) {
+	var result synonymSet
+
+	if err := c.doRequest(ctx, http.MethodPut, "/synonym_sets/"+url.PathEscape(name), set, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// retrieveSynonymSet retrieves a synonym set with all of its items.
+func (c *TypesenseClient) retrieveSynonymSet(ctx context.Context, name string) (*synonymSet, error) {
+	var result synonymSet
+
+	if err := c.doRequest(ctx, http.MethodGet, "/synonym_sets/"+url.PathEscape(name), nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// deleteSynonymSet deletes a synonym set.
+func (c *TypesenseClient) deleteSynonymSet(ctx context.Context, name string) error {
+	return c.doRequest(ctx, http.MethodDelete, "/synonym_sets/"+url.PathEscape(name), nil, nil)
+}
+
+// serverVersion returns the version reported by the debug endpoint of the
+// Typesense server. The version is retrieved once and cached afterwards.
+func (c *TypesenseClient) serverVersion(ctx context.Context) (string, error) {
+	c.versionMutex.Lock()
+	defer c.versionMutex.Unlock()
+
+	if c.version != "" {
+		return c.version, nil
+	}
+
+	response, err := c.api.Debug(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var debug struct {
+		Version string `json:"version"`
+	}
+
+	if err := decodeResponse(response, &debug); err != nil {
+		return "", err
+	}
+
+	c.version = debug.Version
+
+	return c.version, nil
+}
+
+// requireServerVersion reports an error on attributePath when the Typesense
+// server is older than the given major version. A warning is reported instead
+// when the server version cannot be determined.
+func (c *TypesenseClient) requireServerVersion(ctx context.Context, major int, feature string, attributePath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	version, err := c.serverVersion(ctx)
+
+	if err != nil {
+		diags.AddAttributeWarning(attributePath, "Unknown Server Version",
+			fmt.Sprintf("Unable to determine the Typesense server version, %s requires at least v%d. Got error: %s", feature, major, err))
+		return diags
+	}
+
+	if actual, ok := majorVersion(version); ok && actual < major {
+		diags.AddAttributeError(attributePath, "Unsupported Server Version",
+			fmt.Sprintf("%s requires Typesense v%d or later, but the server runs %s.", feature, major, version))
+	}
+
+	return diags
+}
+
+// requireServerVersionBefore reports an error on attributePath when the
+// Typesense server runs the given major version or a later one, in which the
+// feature is no longer available. Servers whose version cannot be determined
+// are not rejected.
+func (c *TypesenseClient) requireServerVersionBefore(ctx context.Context, major int, feature string, replacement string, attributePath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	version, err := c.serverVersion(ctx)
+
+	if err != nil {
+		return diags
+	}
+
+	if actual, ok := majorVersion(version); ok && actual >= major {
+		diags.AddAttributeError(attributePath, "Unsupported Server Version",
+			fmt.Sprintf("%s is not available since Typesense v%d, but the server runs %s. %s", feature, major, version, replacement))
+	}
+
+	return diags
+}
+
+// majorVersion returns the major version of a Typesense version string.
+// Versions before v26 were released as 0.x and are reported with their minor
+// version as major, e.g. 0.25.2 is reported as 25. Versions which cannot be
+// parsed, like nightly builds, report false.
+func majorVersion(version string) (int, bool) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, false
+	}
+
+	if major == 0 && len(parts) > 1 {
+		minor, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, false
+		}
+
+		return minor, true
+	}
+
+	return major, true
+}
+
+// exportDocuments exports the documents of a collection which match filterBy.
+func (c *TypesenseClient) exportDocuments(ctx context.Context, collectionName string, filterBy string) ([]map[string]interface{}, error) {
+	response, err := c.bulkApi.ExportDocuments(ctx, collectionName, &api.ExportDocumentsParams{FilterBy: &filterBy})
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(response.Body)
+		return nil, &typesense.HTTPError{Status: response.StatusCode, Body: body}
+	}
+
+	documents := []map[string]interface{}{}
+	decoder := json.NewDecoder(response.Body)
+	decoder.UseNumber()
+
+	for decoder.More() {
+		var document map[string]interface{}
+
+		if err := decoder.Decode(&document); err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+// retrieveDocument retrieves a document and keeps numbers as json.Number, so
+// that large integers are not rounded to float64. The excludeFields are not
+// returned by the server.
+func (c *TypesenseClient) retrieveDocument(ctx context.Context, collectionName string, documentId string, excludeFields []string) (map[string]interface{}, error) {
+	query := url.Values{}
+	if len(excludeFields) > 0 {
+		query.Set("exclude_fields", strings.Join(excludeFields, ","))
+	}
+
+	response, err := c.api.GetDocument(ctx, collectionName, documentId, withQuery(query))
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, &typesense.HTTPError{Status: response.StatusCode, Body: body}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document map[string]interface{}
+
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// indexDocument writes a document with the given import action, one of
+// create, upsert, update or emplace. dirtyValues is only sent when not empty.
+func (c *TypesenseClient) indexDocument(ctx context.Context, collectionName string, document map[string]interface{}, action string, dirtyValues string) (map[string]interface{}, error) {
+	body, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("action", action)
+
+	if dirtyValues != "" {
+		query.Set("dirty_values", dirtyValues)
+	}
+
+	response, err := c.api.IndexDocumentWithBody(ctx, collectionName, nil, "application/json", bytes.NewReader(body), withQuery(query))
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+
+	if err := decodeResponse(response, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// countDocuments returns the number of documents of a collection which match
+// filterBy, without fetching any of them.
+func (c *TypesenseClient) countDocuments(ctx context.Context, collectionName string, filterBy string) (int, error) {
+	query := "*"
+	perPage := 0
+
+	result, err := c.Collection(collectionName).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:        query,
+		FilterBy: &filterBy,
+		PerPage:  &perPage,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Found == nil {
+		return 0, nil
+	}
+
+	return *result.Found, nil
+}
+
+// idFilter returns a filter_by expression matching the documents with the
+// given ids, which must not contain backticks.
+func idFilter(ids []string) string {
+	quoted := make([]string, len(ids))
+
+	for i, id := range ids {
+		quoted[i] = "`" + id + "`"
+	}
+
+	return "id:[" + strings.Join(quoted, ",") + "]"
+}
+
+// withQuery returns a request editor which adds the given query parameters to
+// a request built by the generated API client.
+func withQuery(params url.Values) api.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		query := req.URL.Query()
+
+		for key, values := range params {
+			for _, value := range values {
+				query.Add(key, value)
+			}
+		}
+
+		req.URL.RawQuery = query.Encode()
+
+		return nil
+	}
+}
+
+// doRequest sends a JSON request to an endpoint the generated API client does
+// not know about. The request is authenticated like the generated requests
+// and the response is decoded with decodeResponse.
+func (c *TypesenseClient) doRequest(ctx context.Context, method string, operationPath string, body interface{}, result interface{}) error {
+	serverURL, err := url.Parse(c.api.Server)
+	if err != nil {
+		return err
+	}
+
+	requestURL, err := serverURL.Parse("." + operationPath)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL.String(), reader)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for _, editor := range c.api.RequestEditors {
+		if err := editor(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	response, err := c.api.Client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(response, result)
+}
+
+// decodeResponse decodes a successful JSON response into result and turns
+// any other response into a *typesense.HTTPError, like the typed client does.
+func decodeResponse(response *http.Response, result interface{}) error {
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return &typesense.HTTPError{Status: response.StatusCode, Body: body}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(body, result)
+}
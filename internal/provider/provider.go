@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"time"
 
@@ -13,7 +14,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/typesense/typesense-go/typesense"
+	"github.com/typesense/typesense-go/typesense/api"
+	"github.com/typesense/typesense-go/typesense/api/circuit"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -118,14 +120,43 @@ func (p *TypesenseProvider) Configure(ctx context.Context, req provider.Configur
 	}
 
 	// Create a new typesense client using the configuration values
-	client := typesense.NewClient(
-		typesense.WithServer(api_address),
-		typesense.WithAPIKey(api_key),
-		typesense.WithConnectionTimeout(5*time.Minute),
-		typesense.WithConnectionTimeout(5*time.Second),
-		typesense.WithCircuitBreakerMaxRequests(50),
-		typesense.WithCircuitBreakerInterval(2*time.Minute),
-		typesense.WithCircuitBreakerTimeout(1*time.Minute))
+	httpClient := circuit.NewHTTPClient(
+		circuit.WithHTTPRequestDoer(&http.Client{
+			Timeout: 5 * time.Second,
+		}),
+		circuit.WithCircuitBreaker(circuit.NewGoBreaker(
+			circuit.WithGoBreakerName("typesenseClient"),
+			circuit.WithGoBreakerMaxRequests(50),
+			circuit.WithGoBreakerInterval(2*time.Minute),
+			circuit.WithGoBreakerTimeout(1*time.Minute))))
+
+	apiClient, err := api.NewClient(api_address,
+		api.WithAPIKey(api_key),
+		api.WithHTTPClient(httpClient))
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Typesense API Client",
+			"An unexpected error occurred when creating the Typesense API client: "+err.Error(),
+		)
+		return
+	}
+
+	// Exports and imports of whole collections stream for as long as the
+	// collection needs, they are only bounded by the request context.
+	bulkApiClient, err := api.NewClient(api_address,
+		api.WithAPIKey(api_key),
+		api.WithHTTPClient(&http.Client{}))
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Typesense API Client",
+			"An unexpected error occurred when creating the Typesense API client: "+err.Error(),
+		)
+		return
+	}
+
+	client := NewTypesenseClient(apiClient, bulkApiClient)
 
 	// Make the Typesense client available during DataSource and Resource
 	// type Configure methods.
@@ -138,7 +169,14 @@ func (p *TypesenseProvider) Resources(_ context.Context) []func() resource.Resou
 	return []func() resource.Resource{
 		NewCollectionResource,
 		NewSynonymResource,
+		NewSynonymSetResource,
+		NewSynonymsFileResource,
+		NewCollectionSynonymsResource,
+		NewOverrideResource,
 		NewDocumentResource,
+		NewDocumentsResource,
+		NewDocumentsDeleteByFilterResource,
+		NewDocumentsPatchResource,
 		NewAliasResource,
 	}
 }
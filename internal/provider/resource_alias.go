@@ -13,7 +13,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	"github.com/typesense/typesense-go/typesense"
 	"github.com/typesense/typesense-go/typesense/api"
 )
 
@@ -26,7 +25,7 @@ func NewAliasResource() resource.Resource {
 }
 
 type AliasResource struct {
-	client *typesense.Client
+	client *TypesenseClient
 }
 
 type AliasResourceModel struct {
@@ -75,12 +74,12 @@ func (r *AliasResource) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*typesense.Client)
+	client, ok := req.ProviderData.(*TypesenseClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -1,17 +1,26 @@
 package provider
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -20,20 +29,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	"github.com/typesense/typesense-go/typesense"
 	"github.com/typesense/typesense-go/typesense/api"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &CollectionResource{}
 var _ resource.ResourceWithImportState = &CollectionResource{}
+var _ resource.ResourceWithValidateConfig = &CollectionResource{}
+var _ resource.ResourceWithModifyPlan = &CollectionResource{}
 
 func NewCollectionResource() resource.Resource {
 	return &CollectionResource{}
 }
 
 type CollectionResource struct {
-	client *typesense.Client
+	client *TypesenseClient
 }
 
 type CollectionResourceModel struct {
@@ -44,16 +54,26 @@ type CollectionResourceModel struct {
 	EnableNestedFields  types.Bool                     `tfsdk:"enable_nested_fields"`
 	SymbolsToIndex      []types.String                 `tfsdk:"symbols_to_index"`
 	TokenSeparators     []types.String                 `tfsdk:"token_separators"`
+	PreserveDocuments   types.Bool                     `tfsdk:"preserve_documents_on_replace"`
+	SourceCollection    types.String                   `tfsdk:"source_collection"`
+	VoiceQueryModel     []CollectionVoiceQueryModel    `tfsdk:"voice_query_model"`
+	SynonymSets         []types.String                 `tfsdk:"synonym_sets"`
+	DocumentSpoolFile   types.String                   `tfsdk:"document_spool_file"`
+}
+
+type CollectionVoiceQueryModel struct {
+	ModelName types.String `tfsdk:"model_name"`
 }
 
 type CollectionResourceFieldModel struct {
-	Name     types.String `tfsdk:"name"`
-	Facet    types.Bool   `tfsdk:"facet"`
-	Index    types.Bool   `tfsdk:"index"`
-	Optional types.Bool   `tfsdk:"optional"`
-	Sort     types.Bool   `tfsdk:"sort"`
-	Infix    types.Bool   `tfsdk:"infix"`
-	Type     types.String `tfsdk:"type"`
+	Name      types.String `tfsdk:"name"`
+	Facet     types.Bool   `tfsdk:"facet"`
+	Index     types.Bool   `tfsdk:"index"`
+	Optional  types.Bool   `tfsdk:"optional"`
+	Sort      types.Bool   `tfsdk:"sort"`
+	Infix     types.Bool   `tfsdk:"infix"`
+	Type      types.String `tfsdk:"type"`
+	Reference types.String `tfsdk:"reference"`
 }
 
 func (r *CollectionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -89,7 +109,7 @@ func (r *CollectionResource) Schema(ctx context.Context, req resource.SchemaRequ
 			"enable_nested_fields": schema.BoolAttribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "Enable nested fields, must be enabled to use object/object[] types",
+				MarkdownDescription: "Enable nested fields, must be enabled to use object/object[] types. Subfields which the server derives from object fields are not tracked, unless they are declared explicitly, e.g. address.city to enable faceting on it",
 				Default:             booldefault.StaticBool(false),
 			},
 			"symbols_to_index": schema.ListAttribute{
@@ -112,8 +132,55 @@ func (r *CollectionResource) Schema(ctx context.Context, req resource.SchemaRequ
 					listplanmodifier.RequiresReplace(),
 				},
 			},
+			"preserve_documents_on_replace": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Export the documents before the collection is deleted and import them into the new collection after it is created, so that changes which require a replacement do not wipe the data. Documents are kept in the local document_spool_file until they have been imported, when the import fails they are imported again with the next apply, which replaces the tainted collection, and when the creation fails they are imported by the next creation of the collection. Plan and apply must run on the same machine. Documents are not exported when the collection is destroyed without a replacement, nor with create_before_destroy",
+				Default:             booldefault.StaticBool(false),
+			},
+			"document_spool_file": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Local file which holds the documents exported from the replaced collection until they are imported, only set while the collection is replaced with preserve_documents_on_replace",
+			},
+			"source_collection": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Name of an existing collection whose schema is cloned when this collection is created. The source is only used on creation, changing it replaces the collection and removing it from the configuration keeps the collection as it is. Collection level settings are inherited from the source and only the declared fields are managed, declared fields are applied on top of the cloned schema and removing them from the configuration does not drop them",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							resp.RequiresReplace = !req.ConfigValue.IsNull()
+						},
+						"Changing the source collection replaces the collection, removing it does not.",
+						"Changing the source collection replaces the collection, removing it does not.",
+					),
+				},
+			},
+			"synonym_sets": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Names of the synonym sets used when searching the collection, requires Typesense v30 or later",
+			},
 		},
 		Blocks: map[string]schema.Block{
+			"voice_query_model": schema.ListNestedBlock{
+				MarkdownDescription: "Speech-to-text model used to transcribe voice queries, requires Typesense v27 or later",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"model_name": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the voice query model, e.g. ts/whisper/base.en",
+						},
+					},
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
 			"fields": schema.SetNestedBlock{
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
@@ -124,41 +191,30 @@ func (r *CollectionResource) Schema(ctx context.Context, req resource.SchemaRequ
 							Optional: true,
 							Computed: true,
 							Description: "Facet field",
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"index": schema.BoolAttribute{
 							Optional:    true,
 							Computed:    true,
 							Description: "Index field",
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"optional": schema.BoolAttribute{
 							Optional:    true,
 							Computed:    true,
-							Description: "Optional field",
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
+							Description: "Optional field, defaults to true for fields with a dynamic name or type",
 						},
 						"sort": schema.BoolAttribute{
 							Optional:    true,
 							Computed:    true,
-							Description: "Sort field",
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
+							Description: "Sort field, defaults to true for numeric, bool and geopoint fields",
 						},
 						"infix": schema.BoolAttribute{
 							Optional:    true,
 							Computed:    true,
 							Description: "Infix field",
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
+						},
+						"reference": schema.StringAttribute{
+							Optional:    true,
+							Description: "Field of another collection this field references for JOINs, in the format <collection>.<field>. The referenced collection must exist, build the reference from the id of the typesense_collection resource when both are created by the same apply",
 						},
 						"type": schema.StringAttribute{
 							Required:    true,
@@ -197,12 +253,12 @@ func (r *CollectionResource) Configure(ctx context.Context, req resource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*typesense.Client)
+	client, ok := req.ProviderData.(*TypesenseClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -221,7 +277,7 @@ func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	schema := &api.CollectionSchema{}
+	schema := &collectionSchema{}
 	schema.Name = data.Name.ValueString()
 	schema.DefaultSortingField = data.DefaultSortingField.ValueStringPointer()
 	schema.EnableNestedFields = data.EnableNestedFields.ValueBoolPointer()
@@ -245,7 +301,27 @@ func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 
 	schema.Fields = fields
-	collection, err := r.client.Collections().Create(ctx, schema)
+
+	if len(data.VoiceQueryModel) > 0 {
+		schema.VoiceQueryModel = &voiceQueryModel{
+			ModelName: data.VoiceQueryModel[0].ModelName.ValueString(),
+		}
+	}
+
+	if len(data.SynonymSets) > 0 {
+		schema.SynonymSets = convertTerraformArrayToStringArray(data.SynonymSets)
+	}
+
+	if data.SourceCollection.IsUnknown() {
+		data.SourceCollection = types.StringNull()
+	}
+
+	if !data.SourceCollection.IsNull() {
+		r.createClonedCollection(ctx, &data, resp)
+		return
+	}
+
+	collection, err := r.client.createCollection(ctx, schema, "")
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create collection, got error: %s", err))
@@ -260,7 +336,9 @@ func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 
 	data.EnableNestedFields = types.BoolPointerValue(collection.EnableNestedFields)
-	data.Fields = flattenCollectionFields(collection.Fields)
+	data.Fields = withoutDerivedSubfields(flattenCollectionFields(collection.Fields), data.Fields)
+	data.VoiceQueryModel = flattenVoiceQueryModel(collection.VoiceQueryModel)
+	data.SynonymSets = flattenSynonymSets(collection.SynonymSets, data.SynonymSets)
 
 	data.SymbolsToIndex = []types.String{}
 	if collection.SymbolsToIndex != nil {
@@ -277,6 +355,7 @@ func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(r.restoreDocuments(ctx, collection.Name, data.DocumentSpoolFile)...)
 }
 
 func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -291,7 +370,7 @@ func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	id := data.Id.ValueString()
 
-	collection, err := r.client.Collection(id).Retrieve(ctx)
+	collection, err := r.client.retrieveCollection(ctx, id)
 
 	if err != nil {
 		if strings.Contains(err.Error(), "Not Found") {
@@ -307,13 +386,29 @@ func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	data.Id = types.StringValue(collection.Name)
 	data.Name = types.StringValue(collection.Name)
+	data.SynonymSets = flattenSynonymSets(collection.SynonymSets, data.SynonymSets)
+
+	// the spool file is no longer tracked once its documents were imported
+	if !spoolFileExists(data.DocumentSpoolFile.ValueString()) {
+		data.DocumentSpoolFile = types.StringNull()
+	}
+
+	if !data.SourceCollection.IsNull() {
+		// collection level settings of a cloned collection are inherited from
+		// the source, only the declared fields are tracked
+		data.Fields = filterCollectionFields(flattenCollectionFields(collection.Fields), data.Fields)
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
 	if collection.DefaultSortingField != nil && *collection.DefaultSortingField != "" {
 		data.DefaultSortingField = types.StringPointerValue(collection.DefaultSortingField)
 	}
 
 	data.EnableNestedFields = types.BoolPointerValue(collection.EnableNestedFields)
-	data.Fields = flattenCollectionFields(collection.Fields)
+	data.Fields = withoutDerivedSubfields(flattenCollectionFields(collection.Fields), data.Fields)
+	data.VoiceQueryModel = flattenVoiceQueryModel(collection.VoiceQueryModel)
 	
 	if collection.SymbolsToIndex != nil {
 		data.SymbolsToIndex = []types.String{}
@@ -336,6 +431,26 @@ func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest,
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// flattenSynonymSets keeps synonym_sets null when no sets are linked and none
+// are configured.
+func flattenSynonymSets(sets []string, current []types.String) []types.String {
+	if len(sets) == 0 && current == nil {
+		return nil
+	}
+
+	return convertStringArrayToTerraformArray(sets)
+}
+
+func flattenVoiceQueryModel(model *voiceQueryModel) []CollectionVoiceQueryModel {
+	if model == nil {
+		return make([]CollectionVoiceQueryModel, 0)
+	}
+
+	return []CollectionVoiceQueryModel{
+		{ModelName: types.StringValue(model.ModelName)},
+	}
+}
+
 func flattenCollectionFields(fields []api.Field) []CollectionResourceFieldModel {
 	if fields != nil {
 		fis := make([]CollectionResourceFieldModel, len(fields))
@@ -348,8 +463,12 @@ func flattenCollectionFields(fields []api.Field) []CollectionResourceFieldModel
 			field.Optional = types.BoolPointerValue(fieldResponse.Optional)
 			field.Sort = types.BoolPointerValue(fieldResponse.Sort)
 			field.Infix = types.BoolPointerValue(fieldResponse.Infix)
+			field.Reference = types.StringNull()
+			if fieldResponse.Reference != nil && *fieldResponse.Reference != "" {
+				field.Reference = types.StringPointerValue(fieldResponse.Reference)
+			}
 			field.Type = types.StringValue(fieldResponse.Type)
-			fis[i] = field
+			fis[i] = withFieldDefaults(field)
 		}
 
 		return fis
@@ -381,14 +500,30 @@ func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequ
 	var drop = new(bool)
 	*drop = true
 
+	serverFields, err := r.derivedSubfieldNames(ctx, state, plan)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to retrieve collection, got error: %s", err))
+		return
+	}
+
 	for _, field := range plan.Fields {
 		//item not exists, need to create
 		if _, ok := stateItems[field.Name.ValueString()]; !ok {
+			if serverFields[field.Name.ValueString()] {
+				//subfield was derived by the server, needs to be replaced by the declared one
+				schema.Fields = append(schema.Fields,
+					api.Field{
+						Drop: drop,
+						Name: field.Name.ValueString(),
+					})
+			}
+
 			schema.Fields = append(schema.Fields, filedModelToApiField(field))
 
 			tflog.Info(ctx, "###Field will be created: "+field.Name.ValueString())
 
-		} else if stateItems[field.Name.ValueString()] != field {
+		} else if !collectionFieldsEqual(stateItems[field.Name.ValueString()], field) {
 			//item was changed, need to update
 
 			schema.Fields = append(schema.Fields,
@@ -409,6 +544,12 @@ func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequ
 	}
 
 	for _, field := range stateItems {
+		if !state.SourceCollection.IsNull() {
+			//fields of a cloned collection are not dropped, they are no longer managed
+			tflog.Info(ctx, "###Field will no longer be managed: "+field.Name.ValueString())
+			continue
+		}
+
 		schema.Fields = append(schema.Fields,
 			api.Field{
 				Drop: drop,
@@ -417,11 +558,29 @@ func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequ
 		tflog.Info(ctx, "###Field will be deleted: "+field.Name.ValueString())
 	}
 
-	_, err := r.client.Collection(state.Id.ValueString()).Update(ctx, schema)
+	// documents validate against the updated schema
+	r.client.forgetCollection(state.Id.ValueString())
 
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update collection, got error: %s", err))
-		return
+	if len(schema.Fields) > 0 {
+		_, err := r.client.Collection(state.Id.ValueString()).Update(ctx, schema)
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update collection, got error: %s", err))
+			return
+		}
+	}
+
+	planSynonymSets := convertTerraformArrayToStringArray(plan.SynonymSets)
+
+	if !slices.Equal(planSynonymSets, convertTerraformArrayToStringArray(state.SynonymSets)) {
+		tflog.Info(ctx, "###Synonym sets will be updated: "+strings.Join(planSynonymSets, ","))
+
+		err := r.client.updateCollection(ctx, state.Id.ValueString(), map[string][]string{"synonym_sets": planSynonymSets})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update synonym sets of collection, got error: %s", err))
+			return
+		}
 	}
 
 	plan.Id = types.StringValue(state.Id.ValueString())
@@ -429,6 +588,253 @@ func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequ
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+func (r *CollectionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Documents are never spooled when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, documentSpoolKey, nil)...)
+		return
+	}
+
+	resp.Diagnostics.Append(r.planDocumentSpool(ctx, req, resp)...)
+
+	// The source collection is only used on creation, a collection which was
+	// not cloned keeps a null source when other attributes change.
+	if !req.State.Raw.IsNull() {
+		var configSource types.String
+		var stateSource types.String
+
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source_collection"), &configSource)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("source_collection"), &stateSource)...)
+
+		if configSource.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_collection"), stateSource)...)
+		}
+	}
+
+	// Unset field attributes are planned with the defaults Typesense applies
+	// for the field type, so that they compare equal to the server values.
+	var configFields types.Set
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("fields"), &configFields)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !configFields.IsUnknown() {
+		var fields []CollectionResourceFieldModel
+
+		resp.Diagnostics.Append(configFields.ElementsAs(ctx, &fields, false)...)
+
+		for i := range fields {
+			fields[i] = withFieldDefaults(fields[i])
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("fields"), fields)...)
+	}
+
+	// Nothing to validate when the provider is not configured yet
+	if resp.Diagnostics.HasError() || r.client == nil {
+		return
+	}
+
+	var planVoiceQueryModel []CollectionVoiceQueryModel
+	var stateVoiceQueryModel []CollectionVoiceQueryModel
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("voice_query_model"), &planVoiceQueryModel)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("voice_query_model"), &stateVoiceQueryModel)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(planVoiceQueryModel) > 0 && (len(stateVoiceQueryModel) == 0 || !planVoiceQueryModel[0].ModelName.Equal(stateVoiceQueryModel[0].ModelName)) {
+		resp.Diagnostics.Append(r.client.requireServerVersion(ctx, 27, "voice_query_model", path.Root("voice_query_model"))...)
+	}
+
+	var planSynonymSets []types.String
+	var stateSynonymSets []types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("synonym_sets"), &planSynonymSets)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("synonym_sets"), &stateSynonymSets)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(planSynonymSets) > 0 && !slices.Equal(convertTerraformArrayToStringArray(planSynonymSets), convertTerraformArrayToStringArray(stateSynonymSets)) {
+		resp.Diagnostics.Append(r.client.requireServerVersion(ctx, 30, "synonym_sets", path.Root("synonym_sets"))...)
+	}
+
+	var name types.String
+	var planFields []CollectionResourceFieldModel
+	var stateFields []CollectionResourceFieldModel
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("fields"), &planFields)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("fields"), &stateFields)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stateReferences := make(map[string]types.String)
+
+	for _, field := range stateFields {
+		stateReferences[field.Name.ValueString()] = field.Reference
+	}
+
+	for _, field := range planFields {
+		if field.Reference.IsNull() || field.Reference.IsUnknown() {
+			continue
+		}
+
+		// only validate references which are added or changed
+		if reference, ok := stateReferences[field.Name.ValueString()]; ok && reference.Equal(field.Reference) {
+			continue
+		}
+
+		resp.Diagnostics.Append(r.validateReference(ctx, name.ValueString(), field, planFields)...)
+	}
+}
+
+// validateReference checks that the collection referenced by the field exists
+// and that the referenced field has a type compatible with the field.
+func (r *CollectionResource) validateReference(ctx context.Context, collectionName string, field CollectionResourceFieldModel, planFields []CollectionResourceFieldModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	fieldName := field.Name.ValueString()
+	reference := field.Reference.ValueString()
+
+	referencedCollection, referencedField, ok := strings.Cut(reference, ".")
+
+	if !ok || referencedCollection == "" || referencedField == "" {
+		diags.AddAttributeError(path.Root("fields"), "Invalid Reference",
+			fmt.Sprintf("Reference %q of field %s must have the format <collection>.<field>.", reference, fieldName))
+		return diags
+	}
+
+	var referencedFields []CollectionResourceFieldModel
+
+	if referencedCollection == collectionName {
+		referencedFields = planFields
+	} else {
+		collection, err := r.client.retrieveCollection(ctx, referencedCollection)
+
+		if err != nil {
+			if strings.Contains(err.Error(), "Not Found") {
+				diags.AddAttributeError(path.Root("fields"), "Referenced Collection Not Found",
+					fmt.Sprintf("Collection %s referenced by field %s does not exist. When it is created by the same apply, build the reference from the id "+
+						"of the typesense_collection resource which manages it, e.g. \"${typesense_collection.%s.id}.%s\", so that it is known once the collection exists.",
+						referencedCollection, fieldName, referencedCollection, referencedField))
+			} else {
+				diags.AddError("Client Error", fmt.Sprintf("Unable to retrieve referenced collection, got error: %s", err))
+			}
+
+			return diags
+		}
+
+		referencedFields = flattenCollectionFields(collection.Fields)
+	}
+
+	// the id field is implicit and always a string
+	referencedType := ""
+	if referencedField == "id" {
+		referencedType = "string"
+	}
+
+	for _, candidate := range referencedFields {
+		if candidate.Name.ValueString() == referencedField {
+			referencedType = candidate.Type.ValueString()
+		}
+	}
+
+	if referencedType == "" {
+		diags.AddAttributeError(path.Root("fields"), "Invalid Reference",
+			fmt.Sprintf("Field %s references %s, but collection %s has no field %s.", fieldName, reference, referencedCollection, referencedField))
+		return diags
+	}
+
+	if !field.Type.IsUnknown() && !referenceTypesCompatible(field.Type.ValueString(), referencedType) {
+		diags.AddAttributeError(path.Root("fields"), "Invalid Reference",
+			fmt.Sprintf("Field %s of type %s cannot reference %s of type %s.", fieldName, field.Type.ValueString(), reference, referencedType))
+	}
+
+	return diags
+}
+
+// referenceTypesCompatible reports whether a field of fieldType can reference
+// a field of referencedType. Array fields reference single values of the same
+// type and integer types of different sizes are interchangeable.
+func referenceTypesCompatible(fieldType string, referencedType string) bool {
+	normalize := func(fieldType string) string {
+		fieldType = strings.TrimSuffix(fieldType, "[]")
+		fieldType = strings.TrimSuffix(fieldType, "*")
+
+		if fieldType == "int32" {
+			return "int64"
+		}
+
+		return fieldType
+	}
+
+	return normalize(fieldType) == normalize(referencedType)
+}
+
+func (r *CollectionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var sourceCollection types.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source_collection"), &sourceCollection)...)
+
+	if resp.Diagnostics.HasError() || sourceCollection.IsNull() {
+		return
+	}
+
+	var defaultSortingField types.String
+	var enableNestedFields types.Bool
+	var symbolsToIndex types.List
+	var tokenSeparators types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("default_sorting_field"), &defaultSortingField)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("enable_nested_fields"), &enableNestedFields)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("symbols_to_index"), &symbolsToIndex)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("token_separators"), &tokenSeparators)...)
+
+	inherited := map[string]attr.Value{
+		"default_sorting_field": defaultSortingField,
+		"enable_nested_fields":  enableNestedFields,
+		"symbols_to_index":      symbolsToIndex,
+		"token_separators":      tokenSeparators,
+	}
+
+	var voiceQueryModel types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("voice_query_model"), &voiceQueryModel)...)
+
+	if len(voiceQueryModel.Elements()) > 0 {
+		inherited["voice_query_model"] = voiceQueryModel
+	}
+
+	for name, value := range inherited {
+		if !value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid Attribute Combination",
+				fmt.Sprintf("%s cannot be set together with source_collection, the setting is inherited from the source collection.", name),
+			)
+		}
+	}
+}
+
 func (r *CollectionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data CollectionResourceModel
 
@@ -439,8 +845,32 @@ func (r *CollectionResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	// Referencing collections destroyed by the same apply are deleted first,
+	// as they depend on this collection
+	resp.Diagnostics.Append(r.checkNotReferenced(ctx, data.Id.ValueString())...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the spool file is only planned when the collection is replaced
+	spoolPath, diags := privateDocumentSpool(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.deleteDocumentSpool(ctx, data, spoolPath)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Warn(ctx, "###Delete collection with id="+data.Id.ValueString())
 
+	r.client.forgetCollection(data.Id.ValueString())
+
 	_, err := r.client.Collection(data.Id.ValueString()).Delete(ctx)
 
 	if err != nil {
@@ -460,14 +890,627 @@ func (r *CollectionResource) ImportState(ctx context.Context, req resource.Impor
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+// createClonedCollection creates the collection as a clone of the source
+// collection and applies the declared fields on top of the cloned schema.
+func (r *CollectionResource) createClonedCollection(ctx context.Context, data *CollectionResourceModel, resp *resource.CreateResponse) {
+	name := data.Name.ValueString()
+
+	collection, err := r.client.createCollection(ctx, map[string]string{"name": name}, data.SourceCollection.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to clone collection, got error: %s", err))
+		return
+	}
+
+	existing := make(map[string]CollectionResourceFieldModel)
+
+	for _, field := range flattenCollectionFields(collection.Fields) {
+		existing[field.Name.ValueString()] = field
+	}
+
+	schema := &api.CollectionUpdateSchema{}
+
+	var drop = new(bool)
+	*drop = true
+
+	for _, field := range data.Fields {
+		current, ok := existing[field.Name.ValueString()]
+
+		if !ok {
+			schema.Fields = append(schema.Fields, filedModelToApiField(field))
+		} else if !clonedFieldMatches(field, current) {
+			schema.Fields = append(schema.Fields,
+				api.Field{
+					Drop: drop,
+					Name: field.Name.ValueString(),
+				},
+				filedModelToApiField(field))
+		}
+	}
+
+	if len(schema.Fields) > 0 {
+		_, err = r.client.Collection(name).Update(ctx, schema)
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update cloned collection, got error: %s", err))
+			return
+		}
+
+		collection, err = r.client.retrieveCollection(ctx, name)
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to retrieve collection, got error: %s", err))
+			return
+		}
+	}
+
+	if len(data.SynonymSets) > 0 {
+		err = r.client.updateCollection(ctx, name, map[string][]string{"synonym_sets": convertTerraformArrayToStringArray(data.SynonymSets)})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update synonym sets of cloned collection, got error: %s", err))
+			return
+		}
+	}
+
+	data.Id = types.StringValue(collection.Name)
+	data.Name = types.StringValue(collection.Name)
+	data.Fields = filterCollectionFields(flattenCollectionFields(collection.Fields), data.Fields)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.Append(r.restoreDocuments(ctx, collection.Name, data.DocumentSpoolFile)...)
+}
+
+// clonedFieldMatches reports whether a field inherited from the source
+// collection already satisfies every attribute set on the declared field.
+func clonedFieldMatches(declared CollectionResourceFieldModel, current CollectionResourceFieldModel) bool {
+	if declared.Type.ValueString() != current.Type.ValueString() {
+		return false
+	}
+
+	if !declared.Reference.IsNull() && !declared.Reference.Equal(current.Reference) {
+		return false
+	}
+
+	for _, pair := range [][2]types.Bool{
+		{declared.Facet, current.Facet},
+		{declared.Index, current.Index},
+		{declared.Optional, current.Optional},
+		{declared.Sort, current.Sort},
+		{declared.Infix, current.Infix},
+	} {
+		if !pair[0].IsNull() && !pair[0].IsUnknown() && !pair[0].Equal(pair[1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterCollectionFields keeps the fields whose name is present in declared.
+func filterCollectionFields(fields []CollectionResourceFieldModel, declared []CollectionResourceFieldModel) []CollectionResourceFieldModel {
+	names := make(map[string]bool)
+
+	for _, field := range declared {
+		names[field.Name.ValueString()] = true
+	}
+
+	filtered := make([]CollectionResourceFieldModel, 0)
+
+	for _, field := range fields {
+		if names[field.Name.ValueString()] {
+			filtered = append(filtered, field)
+		}
+	}
+
+	return filtered
+}
+
+// withoutDerivedSubfields removes the subfields which the server derived from
+// declared object or object[] fields, e.g. address.city for an address object.
+// Subfields which are declared explicitly are kept.
+func withoutDerivedSubfields(fields []CollectionResourceFieldModel, declared []CollectionResourceFieldModel) []CollectionResourceFieldModel {
+	declaredNames := make(map[string]bool)
+
+	for _, field := range declared {
+		declaredNames[field.Name.ValueString()] = true
+	}
+
+	objectPrefixes := []string{}
+
+	for _, field := range fields {
+		if fieldType := field.Type.ValueString(); fieldType == "object" || fieldType == "object[]" {
+			objectPrefixes = append(objectPrefixes, field.Name.ValueString()+".")
+		}
+	}
+
+	filtered := make([]CollectionResourceFieldModel, 0, len(fields))
+
+	for _, field := range fields {
+		name := field.Name.ValueString()
+		derived := false
+
+		for _, prefix := range objectPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				derived = true
+			}
+		}
+
+		if !derived || declaredNames[name] {
+			filtered = append(filtered, field)
+		}
+	}
+
+	return filtered
+}
+
+// derivedSubfieldNames returns the names of the subfields which exist on the
+// server although they are not managed yet, but are declared by the plan.
+func (r *CollectionResource) derivedSubfieldNames(ctx context.Context, state CollectionResourceModel, plan CollectionResourceModel) (map[string]bool, error) {
+	names := make(map[string]bool)
+
+	stateNames := make(map[string]bool)
+	for _, field := range state.Fields {
+		stateNames[field.Name.ValueString()] = true
+	}
+
+	lookup := false
+	for _, field := range plan.Fields {
+		if !stateNames[field.Name.ValueString()] && strings.Contains(field.Name.ValueString(), ".") {
+			lookup = true
+		}
+	}
+
+	if !lookup {
+		return names, nil
+	}
+
+	collection, err := r.client.retrieveCollection(ctx, state.Id.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range collection.Fields {
+		if !stateNames[field.Name] {
+			names[field.Name] = true
+		}
+	}
+
+	return names, nil
+}
+
+// sortedByDefault lists the field types Typesense makes sortable unless sort
+// is set explicitly.
+var sortedByDefault = map[string]bool{
+	"int32":      true,
+	"int64":      true,
+	"float":      true,
+	"bool":       true,
+	"geopoint":   true,
+	"geopoint[]": true,
+}
+
+// withFieldDefaults replaces unset attributes of the field with the values
+// Typesense uses when they are not set explicitly. Fields with a dynamic name
+// or type are optional by default, numeric fields are sortable by default.
+func withFieldDefaults(field CollectionResourceFieldModel) CollectionResourceFieldModel {
+	if field.Name.IsUnknown() || field.Type.IsUnknown() {
+		return field
+	}
+
+	fieldType := field.Type.ValueString()
+	dynamic := strings.Contains(field.Name.ValueString(), ".*") || fieldType == "auto" || fieldType == "string*"
+
+	withDefault := func(value types.Bool, defaultValue bool) types.Bool {
+		if value.IsNull() {
+			return types.BoolValue(defaultValue)
+		}
+
+		return value
+	}
+
+	field.Facet = withDefault(field.Facet, false)
+	field.Index = withDefault(field.Index, true)
+	field.Optional = withDefault(field.Optional, dynamic)
+	field.Sort = withDefault(field.Sort, sortedByDefault[fieldType])
+	field.Infix = withDefault(field.Infix, false)
+
+	return field
+}
+
+// collectionFieldsEqual compares two fields, treating unset attributes as
+// their default values.
+func collectionFieldsEqual(a CollectionResourceFieldModel, b CollectionResourceFieldModel) bool {
+	return withFieldDefaults(a) == withFieldDefaults(b)
+}
+
 func filedModelToApiField(field CollectionResourceFieldModel) api.Field {
 	return api.Field{
-		Name:     field.Name.ValueString(),
-		Facet:    field.Facet.ValueBoolPointer(),
-		Index:    field.Index.ValueBoolPointer(),
-		Optional: field.Optional.ValueBoolPointer(),
-		Sort:     field.Sort.ValueBoolPointer(),
-		Infix:    field.Infix.ValueBoolPointer(),
-		Type:     field.Type.ValueString(),
+		Name:      field.Name.ValueString(),
+		Facet:     field.Facet.ValueBoolPointer(),
+		Index:     field.Index.ValueBoolPointer(),
+		Optional:  field.Optional.ValueBoolPointer(),
+		Sort:      field.Sort.ValueBoolPointer(),
+		Infix:     field.Infix.ValueBoolPointer(),
+		Type:      field.Type.ValueString(),
+		Reference: field.Reference.ValueStringPointer(),
 	}
 }
+
+// checkNotReferenced reports an error when fields of other collections still
+// reference the collection, before the API rejects the deletion.
+func (r *CollectionResource) checkNotReferenced(ctx context.Context, collectionName string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	collections, err := r.client.Collections().Retrieve(ctx)
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list collections, got error: %s", err))
+		return diags
+	}
+
+	if referencedBy := referencingFields(collections, collectionName); len(referencedBy) > 0 {
+		diags.AddError("Collection Still Referenced",
+			fmt.Sprintf("Collection %s cannot be deleted while it is referenced by the fields %s. "+
+				"Remove the references or destroy the referencing collections first.", collectionName, strings.Join(referencedBy, ", ")))
+	}
+
+	return diags
+}
+
+// referencingFields returns the fields of other collections which reference
+// the named collection. A reference belongs to the collection with the
+// longest name it starts with, as collection names may contain dots.
+func referencingFields(collections []*api.CollectionResponse, collectionName string) []string {
+	referencedBy := []string{}
+
+	for _, collection := range collections {
+		if collection.Name == collectionName {
+			continue
+		}
+
+		for _, field := range collection.Fields {
+			if field.Reference == nil || referencedCollection(collections, *field.Reference) != collectionName {
+				continue
+			}
+
+			referencedBy = append(referencedBy, fmt.Sprintf("%s.%s", collection.Name, field.Name))
+		}
+	}
+
+	return referencedBy
+}
+
+// referencedCollection returns the name of the collection a reference in the
+// format <collection>.<field> points to.
+func referencedCollection(collections []*api.CollectionResponse, reference string) string {
+	name := ""
+
+	for _, collection := range collections {
+		if strings.HasPrefix(reference, collection.Name+".") && len(collection.Name) > len(name) {
+			name = collection.Name
+		}
+	}
+
+	return name
+}
+
+// documentImportBatchSize is the number of documents sent with every import
+// request when documents are restored into a replaced collection.
+const documentImportBatchSize = 1000
+
+// documentSpoolKey is the private state key of the spool file planned for
+// the replacement of a collection. The deletion of the replaced collection
+// exports its documents into this file, the creation of the replacement
+// finds the same file in its planned document_spool_file.
+const documentSpoolKey = "document_spool_file"
+
+// planDocumentSpool plans the spool file which carries the documents of a
+// replaced collection over to its replacement. The file only depends on the
+// collection name, so that the plan of the apply and a saved plan agree, and
+// documents left behind by a failed replacement are imported by the next
+// creation of the collection.
+func (r *CollectionResource) planDocumentSpool(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(resp.Private.SetKey(ctx, documentSpoolKey, nil)...)
+
+	var spoolFile types.String
+
+	if !req.State.Raw.IsNull() {
+		// a pending spool file stays tracked until it has been imported
+		diags.Append(req.State.GetAttribute(ctx, path.Root("document_spool_file"), &spoolFile)...)
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("document_spool_file"), spoolFile)...)
+
+		return diags
+	}
+
+	var name types.String
+	var preserve types.Bool
+
+	diags.Append(resp.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	diags.Append(resp.Plan.GetAttribute(ctx, path.Root("preserve_documents_on_replace"), &preserve)...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	spoolFile = types.StringNull()
+
+	if preserve.ValueBool() && !name.IsUnknown() {
+		spoolPath := documentSpoolPath(name.ValueString())
+		spoolFile = types.StringValue(spoolPath)
+
+		value, _ := json.Marshal(spoolPath)
+		diags.Append(resp.Private.SetKey(ctx, documentSpoolKey, value)...)
+	}
+
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("document_spool_file"), spoolFile)...)
+
+	return diags
+}
+
+// privateDocumentSpool returns the spool file planned for the replacement of
+// the collection, or an empty string when the collection is not replaced.
+func privateDocumentSpool(ctx context.Context, private privateState) (string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, documentSpoolKey)
+
+	if diags.HasError() || len(value) == 0 {
+		return "", diags
+	}
+
+	var spoolPath string
+
+	if err := json.Unmarshal(value, &spoolPath); err != nil {
+		diags.AddError("Spool Error", fmt.Sprintf("Unable to read document spool file from private state, got error: %s", err))
+		return "", diags
+	}
+
+	return spoolPath, diags
+}
+
+// privateState is the private state data passed to the resource operations.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// documentSpoolPath returns the local file that holds the documents of a
+// replaced collection until they are imported into the replacement.
+func documentSpoolPath(collectionName string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "terraform-provider-typesense", url.PathEscape(collectionName)+".jsonl")
+}
+
+// spoolFileExists reports whether the spool file holds documents which were
+// not imported yet.
+func spoolFileExists(spoolPath string) bool {
+	_, err := os.Stat(spoolPath)
+
+	return err == nil
+}
+
+// deleteDocumentSpool spools the documents of the collection when it is
+// replaced. The documents of a collection whose own import failed are still
+// in the spool file, they are kept instead of exporting the incomplete
+// collection. When the collection is destroyed without a replacement its
+// spool file is removed, as no replacement would ever import it.
+func (r *CollectionResource) deleteDocumentSpool(ctx context.Context, data CollectionResourceModel, spoolPath string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if spoolPath == "" {
+		if data.DocumentSpoolFile.ValueString() != "" {
+			diags.Append(removeDocumentSpool(ctx, data.DocumentSpoolFile.ValueString())...)
+		}
+
+		return diags
+	}
+
+	if data.DocumentSpoolFile.ValueString() == spoolPath && spoolFileExists(spoolPath) {
+		tflog.Info(ctx, "###Documents which were never imported are kept in "+spoolPath)
+		return diags
+	}
+
+	return r.spoolDocuments(ctx, data.Id.ValueString(), spoolPath)
+}
+
+// spoolDocuments exports all documents of the collection into the spool
+// file, replacing the file if it already exists.
+func (r *CollectionResource) spoolDocuments(ctx context.Context, collectionName string, spoolPath string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := os.MkdirAll(filepath.Dir(spoolPath), 0o700); err != nil {
+		diags.AddError("Spool Error", fmt.Sprintf("Unable to prepare document spool file, got error: %s", err))
+		return diags
+	}
+
+	export, err := r.client.bulk.Collection(collectionName).Documents().Export(ctx)
+
+	if err != nil {
+		if !strings.Contains(err.Error(), "Not Found") {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to export documents, got error: %s", err))
+		}
+
+		return diags
+	}
+
+	defer export.Close()
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(spoolPath), "export-*")
+	if err != nil {
+		diags.AddError("Spool Error", fmt.Sprintf("Unable to create document spool file, got error: %s", err))
+		return diags
+	}
+
+	_, err = io.Copy(tmpFile, export)
+
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), spoolPath)
+	}
+
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		diags.AddError("Spool Error", fmt.Sprintf("Unable to export documents to %s, got error: %s", spoolPath, err))
+		return diags
+	}
+
+	tflog.Info(ctx, "###Documents exported to "+spoolPath)
+
+	return diags
+}
+
+// restoreDocuments imports the documents of the planned spool file, if the
+// replaced collection exported any. When the import fails the file is kept,
+// the collection is tainted and the documents are imported again when it is
+// replaced.
+func (r *CollectionResource) restoreDocuments(ctx context.Context, collectionName string, spoolFile types.String) diag.Diagnostics {
+	if spoolFile.ValueString() == "" || !spoolFileExists(spoolFile.ValueString()) {
+		return nil
+	}
+
+	return r.importDocumentSpool(ctx, collectionName, spoolFile.ValueString())
+}
+
+// importDocumentSpool imports the documents of the spool file into the
+// collection and removes the file once every document has been imported.
+func (r *CollectionResource) importDocumentSpool(ctx context.Context, collectionName string, spoolPath string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	file, err := os.Open(spoolPath)
+
+	if err != nil {
+		diags.AddError("Spool Error", fmt.Sprintf("Unable to open document spool file, got error: %s", err))
+		return diags
+	}
+
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	var batch bytes.Buffer
+	batchCount := 0
+	exported := int64(0)
+
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+
+		if len(line) > 0 {
+			batch.Write(line)
+			batch.WriteByte('\n')
+			batchCount += 1
+			exported += 1
+		}
+
+		if readErr != nil && readErr != io.EOF {
+			diags.AddError("Spool Error", fmt.Sprintf("Unable to read document spool file, got error: %s", readErr))
+			return diags
+		}
+
+		if batchCount == documentImportBatchSize || (readErr == io.EOF && batchCount > 0) {
+			diags.Append(r.importDocumentsBatch(ctx, collectionName, batch.Bytes())...)
+
+			batch.Reset()
+			batchCount = 0
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	collection, err := r.client.Collection(collectionName).Retrieve(ctx)
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to retrieve collection, got error: %s", err))
+		return diags
+	}
+
+	imported := int64(0)
+	if collection.NumDocuments != nil {
+		imported = *collection.NumDocuments
+	}
+
+	if imported != exported {
+		diags.AddError(
+			"Document Count Mismatch",
+			fmt.Sprintf("Exported %d documents from the replaced collection, but collection %s holds %d documents after the import. "+
+				"The exported documents are kept in %s and are imported again when the tainted collection is replaced.", exported, collectionName, imported, spoolPath),
+		)
+
+		return diags
+	}
+
+	file.Close()
+
+	if err := os.Remove(spoolPath); err != nil {
+		diags.AddWarning("Spool Error", fmt.Sprintf("Unable to remove document spool file %s, got error: %s", spoolPath, err))
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("###Imported %d documents into collection %s", imported, collectionName))
+
+	return diags
+}
+
+// removeDocumentSpool removes the spool file of documents which were never
+// imported, when the collection is destroyed without being replaced.
+func removeDocumentSpool(ctx context.Context, spoolPath string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := os.Remove(spoolPath); err != nil {
+		if !os.IsNotExist(err) {
+			diags.AddWarning("Spool Error", fmt.Sprintf("Unable to remove document spool file %s, got error: %s", spoolPath, err))
+		}
+
+		return diags
+	}
+
+	tflog.Info(ctx, "###Removed documents which were never imported from "+spoolPath)
+
+	return diags
+}
+
+// importDocumentsBatch imports a batch of JSONL documents and reports every
+// document which could not be imported.
+func (r *CollectionResource) importDocumentsBatch(ctx context.Context, collectionName string, body []byte) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	action := "create"
+	batchSize := documentImportBatchSize
+
+	result, err := r.client.bulk.Collection(collectionName).Documents().ImportJsonl(ctx, bytes.NewReader(body), &api.ImportDocumentsParams{
+		Action:    &action,
+		BatchSize: &batchSize,
+	})
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to import documents, got error: %s", err))
+		return diags
+	}
+
+	defer result.Close()
+
+	decoder := json.NewDecoder(result)
+
+	for decoder.More() {
+		var line api.ImportDocumentResponse
+
+		if err := decoder.Decode(&line); err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to parse import response, got error: %s", err))
+			return diags
+		}
+
+		if !line.Success {
+			diags.AddError("Import Error", fmt.Sprintf("Unable to import document %s, got error: %s", line.Document, line.Error))
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,417 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CollectionSynonymsResource{}
+var _ resource.ResourceWithImportState = &CollectionSynonymsResource{}
+var _ resource.ResourceWithModifyPlan = &CollectionSynonymsResource{}
+
+func NewCollectionSynonymsResource() resource.Resource {
+	return &CollectionSynonymsResource{}
+}
+
+type CollectionSynonymsResource struct {
+	client *TypesenseClient
+}
+
+type CollectionSynonymsResourceModel struct {
+	Id             types.String                      `tfsdk:"id"`
+	CollectionName types.String                      `tfsdk:"collection_name"`
+	Synonyms       map[string]CollectionSynonymModel `tfsdk:"synonyms"`
+}
+
+type CollectionSynonymModel struct {
+	Root           types.String   `tfsdk:"root"`
+	Synonyms       SynonymsValue  `tfsdk:"synonyms"`
+	Locale         types.String   `tfsdk:"locale"`
+	SymbolsToIndex []types.String `tfsdk:"symbols_to_index"`
+	Type           types.String   `tfsdk:"type"`
+}
+
+func (r *CollectionSynonymsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_collection_synonyms"
+}
+
+func (r *CollectionSynonymsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages every synonym of a collection. Synonyms which are not declared, e.g. because they were added with the dashboard, are shown in the plan and deleted on apply. Do not combine it with typesense_synonym or typesense_synonyms_file on the same collection",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Id identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "Collection name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"synonyms": schema.MapNestedAttribute{
+				Required:            true,
+				MarkdownDescription: "Synonyms of the collection by synonym name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"root": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "For 1-way synonyms, indicates the root word that words in the synonyms parameter map to",
+						},
+						"synonyms": schema.ListAttribute{
+							Required:            true,
+							ElementType:         types.StringType,
+							CustomType:          NewSynonymsType(),
+							Validators:          []validator.List{listvalidator.SizeAtLeast(1), synonymsValidator{}},
+							MarkdownDescription: "Array of words that should be considered as synonyms. The order of the words does not matter, they must be unique and must not contain the root",
+						},
+						"locale": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Locale of the synonym, e.g. `ja` or `th`",
+						},
+						"symbols_to_index": schema.ListAttribute{
+							Optional:            true,
+							ElementType:         types.StringType,
+							MarkdownDescription: "Special characters which are indexed as part of the synonym words, e.g. `+` for `c++`",
+							Validators:          []validator.List{listvalidator.SizeAtLeast(1)},
+						},
+						"type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Either one-way when root is set, or multi-way",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *CollectionSynonymsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*TypesenseClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *CollectionSynonymsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is written to the server when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var synonyms types.Map
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("synonyms"), &synonyms)...)
+
+	for id := range synonyms.Elements() {
+		var root types.String
+
+		synonymPath := path.Root("synonyms").AtMapKey(id)
+
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, synonymPath.AtName("root"), &root)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, synonymPath.AtName("type"), synonymType(root))...)
+	}
+
+	if resp.Diagnostics.HasError() || r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.client.requireServerVersionBefore(ctx, 30, "The per-collection synonyms API", "Use typesense_synonym_set and link the set to the collection with synonym_sets instead.", path.Root("collection_name"))...)
+
+	// Existing synonyms are part of the state once the resource is created,
+	// before that the plan does not show the synonyms which will be deleted.
+	if resp.Diagnostics.HasError() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	var collectionName types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("collection_name"), &collectionName)...)
+
+	if resp.Diagnostics.HasError() || collectionName.IsUnknown() || synonyms.IsUnknown() {
+		return
+	}
+
+	existing, err := r.client.listSynonyms(ctx, collectionName.ValueString())
+
+	if err != nil {
+		// The collection may be created in the same apply
+		return
+	}
+
+	undeclared := []string{}
+
+	for _, synonym := range existing {
+		if synonym.Id == nil {
+			continue
+		}
+
+		if _, ok := synonyms.Elements()[*synonym.Id]; !ok {
+			undeclared = append(undeclared, *synonym.Id)
+		}
+	}
+
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
+
+		resp.Diagnostics.AddAttributeWarning(path.Root("synonyms"), "Undeclared Synonyms Will Be Deleted",
+			fmt.Sprintf("Collection %s has synonyms which are not declared, they will be deleted: %s", collectionName.ValueString(), strings.Join(undeclared, ", ")))
+	}
+}
+
+func (r *CollectionSynonymsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CollectionSynonymsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	existing, err := r.client.listSynonyms(ctx, data.CollectionName.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to retrieve synonyms, got error: %s", err))
+		return
+	}
+
+	current := flattenCollectionSynonyms(existing)
+
+	resp.Diagnostics.Append(r.applySynonyms(ctx, data.CollectionName.ValueString(), current, data.Synonyms)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(data.CollectionName.ValueString())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CollectionSynonymsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CollectionSynonymsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	synonyms, err := r.client.listSynonyms(ctx, data.Id.ValueString())
+
+	if err != nil {
+		if strings.Contains(err.Error(), "Not Found") {
+			resp.State.RemoveResource(ctx)
+			resp.Diagnostics.AddWarning("Resource Not Found", fmt.Sprintf("Unable to find collection %s, removing synonyms from state", data.Id.ValueString()))
+		} else {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to retrieve synonyms, got error: %s", err))
+		}
+
+		return
+	}
+
+	// Every synonym of the collection is tracked, so that undeclared ones
+	// are planned for deletion
+	data.CollectionName = types.StringValue(data.Id.ValueString())
+	data.Synonyms = flattenCollectionSynonyms(synonyms)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CollectionSynonymsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan CollectionSynonymsResourceModel
+	var state CollectionSynonymsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.applySynonyms(ctx, state.Id.ValueString(), state.Synonyms, plan.Synonyms)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(state.Id.ValueString())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *CollectionSynonymsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data CollectionSynonymsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Warn(ctx, fmt.Sprintf("###Delete %d synonyms of collection %s", len(data.Synonyms), data.Id.ValueString()))
+
+	for id := range data.Synonyms {
+		_, err := r.client.Collection(data.Id.ValueString()).Synonym(id).Delete(ctx)
+
+		if err != nil && !strings.Contains(err.Error(), "Not Found") {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete synonym %s, got error: %s", id, err))
+			return
+		}
+	}
+}
+
+func (r *CollectionSynonymsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// applySynonyms writes the declared synonyms which differ from the current
+// ones and deletes every current synonym which is not declared.
+func (r *CollectionSynonymsResource) applySynonyms(ctx context.Context, collectionName string, current map[string]CollectionSynonymModel, declared map[string]CollectionSynonymModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	changed, removed := diffCollectionSynonyms(current, declared)
+
+	tflog.Info(ctx, fmt.Sprintf("###Synonyms will be written: %d, deleted: %d", len(changed), len(removed)))
+
+	for _, id := range changed {
+		_, err := r.client.upsertSynonym(ctx, collectionName, id, expandCollectionSynonym(declared[id]))
+
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to upsert synonym %s, got error: %s", id, err))
+			return diags
+		}
+	}
+
+	for _, id := range removed {
+		_, err := r.client.Collection(collectionName).Synonym(id).Delete(ctx)
+
+		if err != nil && !strings.Contains(err.Error(), "Not Found") {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to delete synonym %s, got error: %s", id, err))
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// diffCollectionSynonyms returns the sorted ids of the declared synonyms
+// which differ from the current ones and of the current synonyms which are
+// not declared.
+func diffCollectionSynonyms(current map[string]CollectionSynonymModel, declared map[string]CollectionSynonymModel) ([]string, []string) {
+	changed := []string{}
+
+	for id, synonym := range declared {
+		existing, ok := current[id]
+
+		if !ok || !collectionSynonymsEqual(existing, synonym) {
+			changed = append(changed, id)
+		}
+	}
+
+	removed := []string{}
+
+	for id := range current {
+		if _, ok := declared[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+
+	sort.Strings(changed)
+	sort.Strings(removed)
+
+	return changed, removed
+}
+
+func expandCollectionSynonym(data CollectionSynonymModel) *synonymSchema {
+	schema := &synonymSchema{}
+
+	schema.Root = data.Root.ValueStringPointer()
+	schema.Synonyms = data.Synonyms.Strings()
+	schema.Locale = data.Locale.ValueStringPointer()
+
+	if len(data.SymbolsToIndex) > 0 {
+		schema.SymbolsToIndex = convertTerraformArrayToStringArray(data.SymbolsToIndex)
+	}
+
+	return schema
+}
+
+// flattenCollectionSynonyms converts the synonyms returned by the server by
+// their id, empty values which the server returns for unset settings are
+// kept null.
+func flattenCollectionSynonyms(synonyms []synonymResponse) map[string]CollectionSynonymModel {
+	result := make(map[string]CollectionSynonymModel, len(synonyms))
+
+	for i := range synonyms {
+		if synonyms[i].Id == nil {
+			continue
+		}
+
+		schema := synonymSchemaFromResponse(&synonyms[i])
+
+		synonym := CollectionSynonymModel{
+			Root:     types.StringPointerValue(schema.Root),
+			Synonyms: NewSynonymsValue(schema.Synonyms),
+			Locale:   types.StringPointerValue(schema.Locale),
+			Type:     synonymType(types.StringPointerValue(schema.Root)),
+		}
+
+		if len(schema.SymbolsToIndex) > 0 {
+			synonym.SymbolsToIndex = convertStringArrayToTerraformArray(schema.SymbolsToIndex)
+		}
+
+		result[*synonyms[i].Id] = synonym
+	}
+
+	return result
+}
+
+// collectionSynonymsEqual compares two synonyms like the server applies
+// them, the order of the synonym words does not matter.
+func collectionSynonymsEqual(a CollectionSynonymModel, b CollectionSynonymModel) bool {
+	schemaA := expandCollectionSynonym(a)
+	schemaB := expandCollectionSynonym(b)
+
+	return a.Root.ValueString() == b.Root.ValueString() &&
+		a.Locale.ValueString() == b.Locale.ValueString() &&
+		slices.Equal(schemaA.SymbolsToIndex, schemaB.SymbolsToIndex) &&
+		synonymWordsEqual(schemaA.Synonyms, schemaB.Synonyms)
+}
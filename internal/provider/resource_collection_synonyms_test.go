@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/typesense/typesense-go/typesense/api"
+)
+
+func TestDiffCollectionSynonyms(t *testing.T) {
+	current := map[string]CollectionSynonymModel{
+		"phones": {
+			Synonyms: NewSynonymsValue([]string{"phone", "mobile"}),
+		},
+		"cpp": {
+			Root:           types.StringValue("c++"),
+			Synonyms:       NewSynonymsValue([]string{"cpp"}),
+			SymbolsToIndex: []types.String{types.StringValue("+")},
+		},
+		"dashboard": {
+			Synonyms: NewSynonymsValue([]string{"a", "b"}),
+		},
+	}
+
+	declared := map[string]CollectionSynonymModel{
+		// Only the order of the words differs
+		"phones": {
+			Synonyms: NewSynonymsValue([]string{"mobile", "phone"}),
+		},
+		"cpp": {
+			Root:     types.StringValue("c++"),
+			Synonyms: NewSynonymsValue([]string{"cpp"}),
+		},
+		"shoes": {
+			Synonyms: NewSynonymsValue([]string{"shoe", "sneaker"}),
+			Locale:   types.StringValue("en"),
+		},
+	}
+
+	changed, removed := diffCollectionSynonyms(current, declared)
+
+	if want := []string{"cpp", "shoes"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+
+	if want := []string{"dashboard"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+
+	changed, removed = diffCollectionSynonyms(declared, declared)
+
+	if len(changed) != 0 || len(removed) != 0 {
+		t.Errorf("diff of equal synonyms = %v, %v, want none", changed, removed)
+	}
+}
+
+func TestFlattenCollectionSynonyms(t *testing.T) {
+	cpp := "cpp"
+	unset := "unset"
+	root := "c++"
+	empty := ""
+
+	synonyms := []synonymResponse{
+		{
+			SearchSynonym:  api.SearchSynonym{Id: &cpp, Root: &root, Synonyms: []string{"cpp"}},
+			SymbolsToIndex: []string{"+"},
+		},
+		{
+			SearchSynonym: api.SearchSynonym{Id: &unset, Root: &empty, Synonyms: []string{"a", "b"}},
+			Locale:        &empty,
+		},
+		{
+			SearchSynonym: api.SearchSynonym{Synonyms: []string{"no", "id"}},
+		},
+	}
+
+	result := flattenCollectionSynonyms(synonyms)
+
+	if len(result) != 2 {
+		t.Fatalf("flattened %d synonyms, want 2", len(result))
+	}
+
+	got := result["cpp"]
+
+	if got.Root.ValueString() != "c++" || !got.Locale.IsNull() {
+		t.Errorf("cpp = root %s, locale %s, want root c++ and a null locale", got.Root, got.Locale)
+	}
+
+	if got.Type.ValueString() != "one-way" {
+		t.Errorf("cpp type = %s, want one-way", got.Type)
+	}
+
+	if !reflect.DeepEqual(got.SymbolsToIndex, []types.String{types.StringValue("+")}) {
+		t.Errorf("cpp symbols_to_index = %v, want [+]", got.SymbolsToIndex)
+	}
+
+	got = result["unset"]
+
+	if !got.Root.IsNull() || !got.Locale.IsNull() || got.SymbolsToIndex != nil {
+		t.Errorf("empty settings are not kept null: %+v", got)
+	}
+
+	if got.Type.ValueString() != "multi-way" {
+		t.Errorf("unset type = %s, want multi-way", got.Type)
+	}
+
+	if !reflect.DeepEqual(got.Synonyms.Strings(), []string{"a", "b"}) {
+		t.Errorf("synonyms = %v, want [a b]", got.Synonyms.Strings())
+	}
+}
@@ -1,10 +1,21 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/typesense/typesense-go/typesense/api"
 )
 
 func TestAccCollectionResource(t *testing.T) {
@@ -47,3 +58,173 @@ func testAccExampleResourceConfig(configurableAttribute string) string {
 // }
 `, configurableAttribute)
 }
+
+// fakeTypesense serves the collection and document endpoints used when the
+// documents of a replaced collection are spooled and restored.
+type fakeTypesense struct {
+	t         *testing.T
+	export    string
+	imported  []string
+	documents int
+}
+
+func (f *fakeTypesense) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/documents/export"):
+		if f.export == "" {
+			f.t.Errorf("unexpected export of %s", r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		io.WriteString(w, f.export)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/documents/import"):
+		body, _ := io.ReadAll(r.Body)
+
+		for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+			f.imported = append(f.imported, line)
+			io.WriteString(w, "{\"success\":true}\n")
+		}
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/collections/"):
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"name":%q,"num_documents":%d,"fields":[],"created_at":0}`, strings.TrimPrefix(r.URL.Path, "/collections/"), f.documents)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeCollectionResource(t *testing.T, fake *fakeTypesense) *CollectionResource {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	apiClient, err := api.NewClient(server.URL, api.WithAPIKey("test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Spool files are written below the user cache directory
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	return &CollectionResource{client: NewTypesenseClient(apiClient, apiClient)}
+}
+
+func TestDocumentSpoolReplacement(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeTypesense{t: t, export: "{\"id\":\"1\"}\n{\"id\":\"2\"}\n", documents: 2}
+	r := newFakeCollectionResource(t, fake)
+
+	spoolPath := documentSpoolPath("products")
+	state := CollectionResourceModel{Id: types.StringValue("products"), DocumentSpoolFile: types.StringNull()}
+
+	if diags := r.deleteDocumentSpool(ctx, state, spoolPath); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	content, err := os.ReadFile(spoolPath)
+	if err != nil {
+		t.Fatalf("spool file was not written: %s", err)
+	}
+
+	if string(content) != fake.export {
+		t.Errorf("spool file = %q, want %q", content, fake.export)
+	}
+
+	if diags := r.restoreDocuments(ctx, "products", types.StringValue(spoolPath)); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if want := []string{`{"id":"1"}`, `{"id":"2"}`}; !reflect.DeepEqual(fake.imported, want) {
+		t.Errorf("imported = %v, want %v", fake.imported, want)
+	}
+
+	if spoolFileExists(spoolPath) {
+		t.Error("spool file was not removed after the import")
+	}
+}
+
+func TestDocumentSpoolCountMismatchKeepsFile(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeTypesense{t: t, documents: 1}
+	r := newFakeCollectionResource(t, fake)
+
+	spoolPath := documentSpoolPath("products")
+
+	if err := os.MkdirAll(filepath.Dir(spoolPath), 0o700); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := os.WriteFile(spoolPath, []byte("{\"id\":\"1\"}\n{\"id\":\"2\"}\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	diags := r.restoreDocuments(ctx, "products", types.StringValue(spoolPath))
+
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Document Count Mismatch" {
+		t.Fatalf("expected a document count mismatch, got %v", diags)
+	}
+
+	if !spoolFileExists(spoolPath) {
+		t.Fatal("spool file was removed although documents are missing")
+	}
+
+	// The replacement of the tainted collection keeps the pending documents
+	// instead of exporting the incomplete collection
+	state := CollectionResourceModel{Id: types.StringValue("products"), DocumentSpoolFile: types.StringValue(spoolPath)}
+
+	if diags := r.deleteDocumentSpool(ctx, state, spoolPath); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if !spoolFileExists(spoolPath) {
+		t.Fatal("pending spool file was removed by the replacement")
+	}
+
+	// A destroy without replacement removes the pending documents
+	if diags := r.deleteDocumentSpool(ctx, state, ""); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if spoolFileExists(spoolPath) {
+		t.Error("spool file was kept after the collection was destroyed")
+	}
+}
+
+func TestRestoreDocumentsWithoutSpoolFile(t *testing.T) {
+	r := newFakeCollectionResource(t, &fakeTypesense{t: t})
+
+	for _, spoolFile := range []types.String{types.StringNull(), types.StringValue(documentSpoolPath("products"))} {
+		if diags := r.restoreDocuments(context.Background(), "products", spoolFile); diags.HasError() {
+			t.Errorf("restoreDocuments(%s): unexpected errors: %v", spoolFile, diags)
+		}
+	}
+}
+
+func TestReferencingFields(t *testing.T) {
+	reference := func(value string) *string { return &value }
+
+	collections := []*api.CollectionResponse{
+		{Name: "customers", Fields: []api.Field{{Name: "id", Type: "string"}}},
+		{Name: "customers.v2", Fields: []api.Field{{Name: "id", Type: "string"}}},
+		{Name: "orders", Fields: []api.Field{
+			{Name: "customer_id", Type: "string", Reference: reference("customers.id")},
+			{Name: "customer_v2_id", Type: "string", Reference: reference("customers.v2.id")},
+		}},
+		{Name: "invoices", Fields: []api.Field{
+			{Name: "customer.id", Type: "string", Reference: reference("customers.id")},
+			{Name: "note", Type: "string"},
+		}},
+	}
+
+	cases := map[string][]string{
+		"customers":    {"orders.customer_id", "invoices.customer.id"},
+		"customers.v2": {"orders.customer_v2_id"},
+		"orders":       {},
+	}
+
+	for name, want := range cases {
+		if got := referencingFields(collections, name); !reflect.DeepEqual(got, want) {
+			t.Errorf("referencingFields(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -1,39 +1,69 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	"github.com/typesense/typesense-go/typesense"
+	"github.com/typesense/typesense-go/typesense/api"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DocumentResource{}
 var _ resource.ResourceWithImportState = &DocumentResource{}
+var _ resource.ResourceWithUpgradeState = &DocumentResource{}
+var _ resource.ResourceWithConfigValidators = &DocumentResource{}
+var _ resource.ResourceWithModifyPlan = &DocumentResource{}
+var _ resource.ResourceWithValidateConfig = &DocumentResource{}
 
 func NewDocumentResource() resource.Resource {
 	return &DocumentResource{}
 }
 
 type DocumentResource struct {
-	client *typesense.Client
+	client *TypesenseClient
 }
 
 type DocumentResourceModel struct {
-	Id             types.String         `tfsdk:"id"`
-	Name           types.String         `tfsdk:"name"`
-	CollectionName types.String         `tfsdk:"collection_name"`
-	Document       jsontypes.Normalized `tfsdk:"document"`
+	Id              types.String         `tfsdk:"id"`
+	Name            types.String         `tfsdk:"name"`
+	CollectionName  types.String         `tfsdk:"collection_name"`
+	Document        jsontypes.Normalized `tfsdk:"document"`
+	Action          types.String         `tfsdk:"action"`
+	DirtyValues     types.String         `tfsdk:"dirty_values"`
+	Ownership       types.String         `tfsdk:"ownership"`
+	RemoveKeys      types.Bool           `tfsdk:"remove_declared_keys_on_destroy"`
+	SourceFile      types.String         `tfsdk:"source_file"`
+	SourceHash      types.String         `tfsdk:"source_hash"`
+	Fields          types.Dynamic        `tfsdk:"fields"`
+	ExcludeFields   []types.String       `tfsdk:"exclude_fields"`
+	ConflictPolicy  types.String         `tfsdk:"conflict_policy"`
+	LastApplied     jsontypes.Normalized `tfsdk:"last_applied"`
+	LastAppliedHash types.String         `tfsdk:"last_applied_hash"`
+	StateStorage    types.String         `tfsdk:"state_storage"`
 }
 
 func (r *DocumentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -42,6 +72,8 @@ func (r *DocumentResource) Metadata(ctx context.Context, req resource.MetadataRe
 
 func (r *DocumentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
+
 		MarkdownDescription: "Every record you index in Typesense is called a Document",
 
 		Attributes: map[string]schema.Attribute{
@@ -67,26 +99,133 @@ func (r *DocumentResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"document": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "Document object in JSON format",
+				Optional:            true,
+				MarkdownDescription: "Document object in JSON format. The id of the document is given by name, an id in the document must be equal to name",
 				CustomType:          jsontypes.NormalizedType{},
 			},
+			"action": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Write action, one of create, upsert, update or emplace. With create the document must not exist yet and later changes replace the whole document with upsert. With update the document must already exist and only the declared keys are changed",
+				Default:             stringdefault.StaticString("create"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("create", "upsert", "update", "emplace"),
+				},
+			},
+			"dirty_values": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How values which do not match the type of their field are handled, one of coerce_or_reject, coerce_or_drop, drop or reject. The server default is coerce_or_reject",
+				Validators: []validator.String{
+					stringvalidator.OneOf("coerce_or_reject", "coerce_or_drop", "drop", "reject"),
+				},
+			},
+			"ownership": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Which part of the document is managed, one of full or declared_keys. With declared_keys only the keys present in `document` are compared and patched, the document is created if it does not exist yet, keys written by others are left alone and keys removed from `document` are set to null",
+				Default:             stringdefault.StaticString("full"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("full", "declared_keys"),
+				},
+			},
+			"remove_declared_keys_on_destroy": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "With ownership declared_keys, set the declared keys to null on destroy instead of deleting the whole document",
+				Default:             booldefault.StaticBool(false),
+			},
+			"source_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path to a JSON file with the document object. The file is read when planning and only its hash is kept in state, changes are detected by comparing hashes",
+			},
+			"source_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "SHA-256 hash of the document read from `source_file`",
+			},
+			"exclude_fields": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Fields which are not read back from the server, e.g. generated fields. Fields the collection generates with `embed` are always excluded",
+			},
+			"conflict_policy": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "How changes made outside of Terraform are handled on update, one of overwrite, fail or merge. With fail the update is aborted when the server document was changed since the last apply, unless it already matches the planned document, only the hash of the last applied document is kept in state for this. With merge changes made by others are kept unless the plan changes the same key, which is reported as a conflict",
+				Default:             stringdefault.StaticString("overwrite"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("overwrite", "fail", "merge"),
+				},
+			},
+			"last_applied": schema.StringAttribute{
+				Computed:            true,
+				CustomType:          jsontypes.NormalizedType{},
+				MarkdownDescription: "Document written by the last apply, used as the base to merge changes made outside of Terraform. Only kept when conflict_policy is merge",
+			},
+			"last_applied_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "SHA-256 hash of the canonical JSON encoding of the document written by the last apply, used to detect changes made outside of Terraform. Only kept when conflict_policy is fail",
+			},
+			"state_storage": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "How the server document is kept in state, one of full or hash. With hash only the canonical hash of the document is kept in `source_hash` and drift shows up as a hash change instead of a JSON diff. Requires `source_file`, as Terraform keeps a document declared with `document` or `fields` in state, and can not be combined with conflict_policy merge, which keeps the last applied document in state",
+				Default:             stringdefault.StaticString("full"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("full", "hash"),
+				},
+			},
+			"fields": schema.DynamicAttribute{
+				Optional:            true,
+				MarkdownDescription: "Document object as a native HCL object, an alternative to `document` which keeps the types of its values. Numbers are sent and read back with their exact value",
+			},
 		},
 	}
 }
 
+func (r *DocumentResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("document"),
+			path.MatchRoot("source_file"),
+			path.MatchRoot("fields"),
+		),
+	}
+}
+
+func (r *DocumentResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var stateStorage types.String
+	var conflictPolicy types.String
+	var sourceFile types.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("state_storage"), &stateStorage)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("conflict_policy"), &conflictPolicy)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source_file"), &sourceFile)...)
+
+	// Terraform keeps every configured attribute in state, only a document
+	// read from a file is left out of it
+	if stateStorage.ValueString() == "hash" && sourceFile.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("state_storage"), "Invalid Attribute Combination",
+			"state_storage hash requires the document to be read from source_file. A document declared with document or fields is part of the configuration, which Terraform always keeps in state.")
+	}
+
+	if stateStorage.ValueString() == "hash" && conflictPolicy.ValueString() == "merge" {
+		resp.Diagnostics.AddAttributeError(path.Root("conflict_policy"), "Invalid Attribute Combination",
+			"A merge needs the last applied document, which is not kept in state when state_storage is hash. Use conflict_policy fail or overwrite instead.")
+	}
+}
+
 func (r *DocumentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*typesense.Client)
+	client, ok := req.ProviderData.(*TypesenseClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -95,43 +234,141 @@ func (r *DocumentResource) Configure(ctx context.Context, req resource.Configure
 	r.client = client
 }
 
-func (r *DocumentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+func (r *DocumentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
 	var data DocumentResourceModel
 
-	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	document, err := parseJsonStringToMap(data.Document.ValueString())
+	if data.SourceFile.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_hash"), types.StringUnknown())...)
+		return
+	}
 
-	if err != nil {
-		resp.Diagnostics.AddError("JSON format error", fmt.Sprintf("Unable to parse document json, got error: %s", err))
+	if data.SourceFile.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_hash"), types.StringNull())...)
+	}
+
+	// Fields may hold values which are only known after apply, e.g. the id of
+	// another resource, the document can not be validated or hashed then
+	if data.Document.IsUnknown() || !valueFullyKnown(data.Fields) || data.Name.IsUnknown() {
 		return
 	}
 
-	document["id"] = data.Name.ValueString()
+	document, diags := declaredDocument(data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	result, err := r.client.Collection(data.CollectionName.ValueString()).Documents().Create(ctx, document)
+	documentPath := declaredDocumentPath(data)
+
+	// The id of the document is given by name
+	if id, ok := document["id"]; ok && id != data.Name.ValueString() {
+		resp.Diagnostics.AddAttributeError(documentPath, "Conflicting Document Id",
+			fmt.Sprintf("The document has the id %v, but the id of the document is given by name %s. Remove the id from the document or set it to name.", id, data.Name.ValueString()))
+		return
+	}
+
+	if !data.SourceFile.IsNull() {
+		hash, err := documentHash(document)
+
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(documentPath, "JSON format error", fmt.Sprintf("Unable to hash document, got error: %s", err))
+			return
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_hash"), hash)...)
+	}
+
+	if r.client == nil || data.CollectionName.IsUnknown() {
+		return
+	}
+
+	collection, err := r.client.cachedCollection(ctx, data.CollectionName.ValueString())
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create document, got error: %s", err))
+		// The collection may be created in the same apply
+		if !strings.Contains(err.Error(), "Not Found") {
+			resp.Diagnostics.AddWarning("Document Not Validated", fmt.Sprintf("Unable to retrieve collection %s, got error: %s", data.CollectionName.ValueString(), err))
+		}
+
 		return
 	}
 
-	data.Id = types.StringValue(createId(data.CollectionName.ValueString(), result["id"].(string)))
+	// Partial writes do not need to hold the required fields
+	partial := data.Action.ValueString() == "update" || data.Action.ValueString() == "emplace" || data.Ownership.ValueString() == "declared_keys"
 
-	delete(result, "id")
+	resp.Diagnostics.Append(validateDocument(data.Name.ValueString(), document, collection.Fields, partial, data.DirtyValues.ValueString(), func(key string) path.Path {
+		if !data.Fields.IsNull() {
+			// nested fields point at the object which holds them
+			if _, ok := document[key]; !ok {
+				key, _, _ = strings.Cut(key, ".")
+			}
 
-	data.Document, err = parseMapToJsonString(result)
+			return path.Root("fields").AtName(key)
+		}
+
+		return documentPath
+	}, documentPath)...)
+}
+
+func (r *DocumentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DocumentResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	document, diags := declaredDocument(data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	document["id"] = data.Name.ValueString()
+
+	action := data.Action.ValueString()
+
+	if data.Ownership.ValueString() == "declared_keys" {
+		// The document is shared with other writers and may exist already,
+		// only the declared keys are written.
+		action = "emplace"
+	}
+
+	result, err := r.client.indexDocument(ctx, data.CollectionName.ValueString(), document, action, data.DirtyValues.ValueString())
 
 	if err != nil {
-		resp.Diagnostics.AddError("JSON format error", fmt.Sprintf("Unable to parse json response, got error: %s", err))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to %s document, got error: %s", action, err))
+		return
+	}
+
+	id, ok := result["id"].(string)
+
+	if !ok {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to %s document, the response has no id", action))
 		return
 	}
 
+	// The response of update and emplace holds the merged document, the
+	// declared document is kept in state and differences show up on Read.
+	data.Id = types.StringValue(createId(data.CollectionName.ValueString(), id))
+
+	resp.Diagnostics.Append(setLastApplied(&data, document)...)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -151,7 +388,14 @@ func (r *DocumentResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	result, err := r.client.Collection(collectionName).Document(id).Retrieve(ctx)
+	excludeFields, diags := r.excludedFields(ctx, collectionName, data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	result, err := r.client.retrieveDocument(ctx, collectionName, id, excludeFields)
 
 	if err != nil {
 		if strings.Contains(err.Error(), "Not Found") {
@@ -169,6 +413,67 @@ func (r *DocumentResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 	delete(result, "id")
 
+	// Older servers ignore exclude_fields on retrieve
+	for _, field := range excludeFields {
+		delete(result, field)
+	}
+
+	declared, declaredDiags := declaredDocument(data)
+
+	if data.Ownership.ValueString() == "declared_keys" {
+		resp.Diagnostics.Append(declaredDiags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		result = projectDocument(result, declared)
+	}
+
+	// Keys declared as null clear the key, the server does not return them,
+	// and an id is only read back when it is declared
+	if !declaredDiags.HasError() {
+		keepDeclaredNulls(result, declared)
+
+		if id, ok := declared["id"]; ok && id == data.Name.ValueString() {
+			result["id"] = id
+		}
+	}
+
+	if data.ConflictPolicy.ValueString() == "merge" && !data.LastApplied.IsNull() {
+		resp.Diagnostics.Append(hideMergedChanges(data, result)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !data.Fields.IsNull() {
+		resp.Diagnostics.Append(r.readFields(ctx, &data, result)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	// Documents read from a file are only tracked by their hash
+	if !data.SourceFile.IsNull() {
+		hash, err := documentHash(result)
+
+		if err != nil {
+			resp.Diagnostics.AddError("JSON format error", fmt.Sprintf("Unable to hash document, got error: %s", err))
+			return
+		}
+
+		data.SourceHash = types.StringValue(hash)
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
 	data.Document, err = parseMapToJsonString(result)
 
 	if err != nil {
@@ -181,18 +486,20 @@ func (r *DocumentResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 func (r *DocumentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data DocumentResourceModel
+	var state DocumentResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	document, err := parseJsonStringToMap(data.Document.ValueString())
+	document, diags := declaredDocument(data)
+	resp.Diagnostics.Append(diags...)
 
-	if err != nil {
-		resp.Diagnostics.AddError("JSON format error", fmt.Sprintf("Unable to parse document json, got error: %s", err))
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -202,23 +509,60 @@ func (r *DocumentResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	document["id"] = id
+	applied := maps.Clone(document)
+	delete(applied, "id")
 
-	result, err := r.client.Collection(collectionName).Document(id).Update(ctx, document)
-	_ = result // result is empty
+	if data.ConflictPolicy.ValueString() == "fail" && !state.LastAppliedHash.IsNull() {
+		resp.Diagnostics.Append(r.checkLastAppliedHash(ctx, collectionName, id, data, state, applied)...)
 
-	if err != nil {
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else if data.ConflictPolicy.ValueString() == "merge" && !state.LastApplied.IsNull() {
+		merged, diags := r.resolveConflicts(ctx, collectionName, id, data, state, applied)
+		resp.Diagnostics.Append(diags...)
 
-		//check if error contains 201 response
-		if strings.Contains(err.Error(), "201") {
-			//ignore, sometimes typesense returns 201 code
-		} else {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update document, got error: %s", err))
+		if resp.Diagnostics.HasError() {
 			return
 		}
 
+		document = merged
+	}
+
+	// The document exists already, so create falls back to upsert which
+	// replaces the whole document.
+	action := data.Action.ValueString()
+	if action == "create" {
+		action = "upsert"
+	}
+
+	if data.Ownership.ValueString() == "declared_keys" {
+		// Only patch the declared keys and clear the keys which are no longer
+		// declared, the rest of the document belongs to other writers.
+		action = "update"
+
+		previous, diags := declaredDocument(state)
+
+		if !diags.HasError() && state.Ownership.ValueString() == "declared_keys" {
+			for key := range previous {
+				if _, ok := document[key]; !ok {
+					document[key] = nil
+				}
+			}
+		}
+	}
+
+	document["id"] = id
+
+	_, err := r.client.indexDocument(ctx, collectionName, document, action, data.DirtyValues.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to %s document, got error: %s", action, err))
+		return
 	}
 
+	resp.Diagnostics.Append(setLastApplied(&data, applied)...)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -238,6 +582,11 @@ func (r *DocumentResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	if data.Ownership.ValueString() == "declared_keys" && data.RemoveKeys.ValueBool() {
+		r.removeDeclaredKeys(ctx, collectionName, id, data, &resp.Diagnostics)
+		return
+	}
+
 	tflog.Warn(ctx, "###Delete Document with id="+data.Id.ValueString())
 
 	_, err := r.client.Collection(collectionName).Document(id).Delete(ctx)
@@ -256,6 +605,641 @@ func (r *DocumentResource) Delete(ctx context.Context, req resource.DeleteReques
 	data.Id = types.StringValue("")
 }
 
+// excludedFields returns the fields which are not read back, the configured
+// exclude_fields and the fields the collection generates with embed.
+func (r *DocumentResource) excludedFields(ctx context.Context, collectionName string, data DocumentResourceModel) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	excludeFields := convertTerraformArrayToStringArray(data.ExcludeFields)
+
+	collection, err := r.client.cachedCollection(ctx, collectionName)
+
+	if err != nil {
+		if !strings.Contains(err.Error(), "Not Found") {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to retrieve collection %s, got error: %s", collectionName, err))
+		}
+
+		return excludeFields, diags
+	}
+
+	for _, field := range collection.Fields {
+		if field.Embed != nil && !slices.Contains(excludeFields, field.Name) {
+			excludeFields = append(excludeFields, field.Name)
+		}
+	}
+
+	return excludeFields, diags
+}
+
+// resolveConflicts compares the server document with the last applied one and
+// returns the document to write. Changes made by others since the last apply
+// are kept, unless the plan changes the same key.
+func (r *DocumentResource) resolveConflicts(ctx context.Context, collectionName string, id string, data DocumentResourceModel, state DocumentResourceModel, applied map[string]interface{}) (map[string]interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	excludeFields, diags := r.excludedFields(ctx, collectionName, data)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	server, err := r.client.retrieveDocument(ctx, collectionName, id, excludeFields)
+
+	if err != nil {
+		if strings.Contains(err.Error(), "Not Found") {
+			// Nothing to conflict with, the document is written again
+			return applied, diags
+		}
+
+		diags.AddError("Client Error", fmt.Sprintf("Unable to retrieve document, got error: %s", err))
+		return nil, diags
+	}
+
+	delete(server, "id")
+
+	for _, field := range excludeFields {
+		delete(server, field)
+	}
+
+	base, err := parseJsonStringToMap(state.LastApplied.ValueString())
+
+	if err != nil {
+		diags.AddError("JSON format error", fmt.Sprintf("Unable to parse last applied document, got error: %s", err))
+		return nil, diags
+	}
+
+	keys := make(map[string]bool)
+
+	for _, document := range []map[string]interface{}{base, applied, server} {
+		for key := range document {
+			keys[key] = true
+		}
+	}
+
+	merged := make(map[string]interface{})
+	conflicts := []string{}
+
+	for key := range keys {
+		baseValue, inBase := base[key]
+		ours, inOurs := applied[key]
+		theirs, inTheirs := server[key]
+
+		// Keys the server has but Terraform never managed belong to others
+		// when only the declared keys are owned
+		if data.Ownership.ValueString() == "declared_keys" && !inBase && !inOurs {
+			continue
+		}
+
+		unchanged := func(inA bool, a interface{}, inB bool, b interface{}) bool {
+			return inA == inB && (!inA || jsonValuesEqual(a, b))
+		}
+
+		switch {
+		case unchanged(inTheirs, theirs, inBase, baseValue) || unchanged(inTheirs, theirs, inOurs, ours):
+			if inOurs {
+				merged[key] = ours
+			}
+		case unchanged(inOurs, ours, inBase, baseValue):
+			if inTheirs {
+				merged[key] = theirs
+			}
+		default:
+			conflicts = append(conflicts, key)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+
+		diags.AddAttributeError(declaredDocumentPath(data), "Document Changed Outside Terraform",
+			fmt.Sprintf("The keys %s of document %s were changed outside of Terraform since the last apply and would be overwritten. Refresh and adapt the configuration or set conflict_policy to overwrite.", strings.Join(conflicts, ", "), id))
+		return nil, diags
+	}
+
+	return merged, diags
+}
+
+// checkLastAppliedHash fails the update when the server document no longer
+// has the hash of the last applied document, unless it already matches the
+// planned document. Without the last applied document a key level comparison
+// is not possible.
+func (r *DocumentResource) checkLastAppliedHash(ctx context.Context, collectionName string, id string, data DocumentResourceModel, state DocumentResourceModel, applied map[string]interface{}) diag.Diagnostics {
+	excludeFields, diags := r.excludedFields(ctx, collectionName, data)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	server, err := r.client.retrieveDocument(ctx, collectionName, id, excludeFields)
+
+	if err != nil {
+		if !strings.Contains(err.Error(), "Not Found") {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to retrieve document, got error: %s", err))
+		}
+
+		return diags
+	}
+
+	delete(server, "id")
+
+	for _, field := range excludeFields {
+		delete(server, field)
+	}
+
+	if state.Ownership.ValueString() == "declared_keys" {
+		previous, previousDiags := declaredDocument(state)
+
+		if !previousDiags.HasError() {
+			server = projectDocument(server, previous)
+		}
+	}
+
+	// Keys declared as null are not returned by the server
+	hash, err := documentHash(withoutNullValues(server))
+
+	if err != nil {
+		diags.AddError("JSON format error", fmt.Sprintf("Unable to hash document, got error: %s", err))
+		return diags
+	}
+
+	appliedHash, err := documentHash(withoutNullValues(applied))
+
+	if err != nil {
+		diags.AddError("JSON format error", fmt.Sprintf("Unable to hash document, got error: %s", err))
+		return diags
+	}
+
+	if hash == state.LastAppliedHash.ValueString() || hash == appliedHash {
+		return diags
+	}
+
+	diags.AddAttributeError(declaredDocumentPath(data), "Document Changed Outside Terraform",
+		fmt.Sprintf("Document %s was changed outside of Terraform since the last apply and would be overwritten. Refresh and adapt the configuration or set conflict_policy to overwrite.", id))
+
+	return diags
+}
+
+// setLastApplied keeps the hash of the applied document for conflict_policy
+// fail and the whole document as the base of a merge for conflict_policy
+// merge.
+func setLastApplied(data *DocumentResourceModel, applied map[string]interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.LastApplied = jsontypes.NewNormalizedNull()
+	data.LastAppliedHash = types.StringNull()
+
+	document := maps.Clone(applied)
+	delete(document, "id")
+
+	if data.ConflictPolicy.ValueString() == "fail" {
+		hash, err := documentHash(withoutNullValues(document))
+
+		if err != nil {
+			diags.AddError("JSON format error", fmt.Sprintf("Unable to hash applied document, got error: %s", err))
+			return diags
+		}
+
+		data.LastAppliedHash = types.StringValue(hash)
+
+		return diags
+	}
+
+	if data.ConflictPolicy.ValueString() != "merge" {
+		return diags
+	}
+
+	lastApplied, err := parseMapToJsonString(document)
+
+	if err != nil {
+		diags.AddError("JSON format error", fmt.Sprintf("Unable to encode applied document, got error: %s", err))
+		return diags
+	}
+
+	data.LastApplied = lastApplied
+
+	return diags
+}
+
+// hideMergedChanges replaces the values which others changed since the last
+// apply with the values in state. With conflict_policy merge these changes are
+// kept on purpose and are no drift.
+func hideMergedChanges(data DocumentResourceModel, result map[string]interface{}) diag.Diagnostics {
+	base, err := parseJsonStringToMap(data.LastApplied.ValueString())
+
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("JSON format error", fmt.Sprintf("Unable to parse last applied document, got error: %s", err))
+		return diags
+	}
+
+	prior, diags := declaredDocument(data)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	keys := make(map[string]bool)
+
+	for key := range result {
+		keys[key] = true
+	}
+
+	for key := range base {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		value, inResult := result[key]
+		baseValue, inBase := base[key]
+
+		if inResult == inBase && (!inResult || jsonValuesEqual(value, baseValue)) {
+			continue
+		}
+
+		if priorValue, ok := prior[key]; ok && key != "id" {
+			result[key] = priorValue
+		} else {
+			delete(result, key)
+		}
+	}
+
+	return diags
+}
+
+// readFields sets fields from the server document. The prior value is kept
+// when it encodes to the same JSON, so that lists and maps declared in the
+// configuration are not turned into the tuples and objects read from JSON.
+func (r *DocumentResource) readFields(ctx context.Context, data *DocumentResourceModel, document map[string]interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	current, err := json.Marshal(document)
+
+	if err != nil {
+		diags.AddError("JSON format error", fmt.Sprintf("Unable to encode document, got error: %s", err))
+		return diags
+	}
+
+	if prior, err := convertTerraformValueToJson(data.Fields); err == nil {
+		if priorJson, err := json.Marshal(prior); err == nil && bytes.Equal(priorJson, current) {
+			return diags
+		}
+	}
+
+	value, err := convertJsonToTerraformValue(document)
+
+	if err != nil {
+		diags.AddError("JSON format error", fmt.Sprintf("Unable to convert document to fields, got error: %s", err))
+		return diags
+	}
+
+	data.Fields = types.DynamicValue(value)
+
+	return diags
+}
+
+// removeDeclaredKeys sets the declared keys of the document to null and keeps
+// the rest of the document.
+func (r *DocumentResource) removeDeclaredKeys(ctx context.Context, collectionName string, id string, data DocumentResourceModel, diags *diag.Diagnostics) {
+	declared, declaredDiags := declaredDocument(data)
+	diags.Append(declaredDiags...)
+
+	if diags.HasError() {
+		return
+	}
+
+	document := map[string]interface{}{"id": id}
+
+	for key := range declared {
+		if key != "id" {
+			document[key] = nil
+		}
+	}
+
+	tflog.Warn(ctx, "###Remove declared keys of Document with id="+data.Id.ValueString())
+
+	_, err := r.client.indexDocument(ctx, collectionName, document, "update", data.DirtyValues.ValueString())
+
+	if err != nil && !strings.Contains(err.Error(), "Not Found") {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to remove declared keys of document, got error: %s", err))
+	}
+}
+
 func (r *DocumentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	collectionName, name, err := splitCollectionRelatedId(req.ID)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unable to split resource ID: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), createId(collectionName, name))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("collection_name"), collectionName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+func (r *DocumentResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 ids joined the collection and the document name with a dot
+		0: {StateUpgrader: upgradeDocumentState},
+	}
+}
+
+// upgradeDocumentState upgrades version 0 state, attributes with a schema
+// default which version 0 did not have are set to their default.
+func upgradeDocumentState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	upgradeCollectionRelatedIdState(ctx, req, resp, func(state map[string]interface{}) {
+		defaults := map[string]interface{}{
+			"action":                          "create",
+			"ownership":                       "full",
+			"remove_declared_keys_on_destroy": false,
+			"conflict_policy":                 "overwrite",
+			"state_storage":                   "full",
+		}
+
+		for key, value := range defaults {
+			if state[key] == nil {
+				state[key] = value
+			}
+		}
+	})
+}
+
+// declaredDocument returns the document declared either inline, as fields or
+// in the source file. An id in the document is replaced by name on writes.
+func declaredDocument(data DocumentResourceModel) (map[string]interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if !data.Fields.IsNull() {
+		value, err := convertTerraformValueToJson(data.Fields)
+		document, ok := value.(map[string]interface{})
+
+		if err != nil {
+			diags.AddAttributeError(path.Root("fields"), "Invalid Fields", fmt.Sprintf("Unable to convert fields, got error: %s", err))
+		} else if !ok {
+			diags.AddAttributeError(path.Root("fields"), "Invalid Fields", "Fields must be an object.")
+		}
+
+		return document, diags
+	}
+
+	if !data.SourceFile.IsNull() {
+		document, err := readSourceDocument(data.SourceFile.ValueString())
+
+		if err != nil {
+			diags.AddAttributeError(path.Root("source_file"), "Invalid Source File", fmt.Sprintf("Unable to read document from %s, got error: %s", data.SourceFile.ValueString(), err))
+		}
+
+		return document, diags
+	}
+
+	document, err := parseJsonStringToMap(data.Document.ValueString())
+
+	if err != nil {
+		diags.AddAttributeError(path.Root("document"), "JSON format error", fmt.Sprintf("Unable to parse document json, got error: %s", err))
+	}
+
+	return document, diags
+}
+
+// readSourceDocument reads a document object from a JSON file.
+func readSourceDocument(name string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+
+	if err := unmarshalJson(content, &document); err != nil {
+		return nil, err
+	}
+
+	if document == nil {
+		return nil, fmt.Errorf("the file does not hold a JSON object")
+	}
+
+	return document, nil
+}
+
+// declaredDocumentPath returns the path of the attribute declaring the document.
+func declaredDocumentPath(data DocumentResourceModel) path.Path {
+	if !data.Fields.IsNull() {
+		return path.Root("fields")
+	}
+
+	if !data.SourceFile.IsNull() {
+		return path.Root("source_file")
+	}
+
+	return path.Root("document")
+}
+
+// validateDocument checks a document against the fields of its collection.
+// Fields with a wildcard name are not checked and required fields are only
+// checked when the whole document is written. Values which the server would
+// coerce to the type of their field are accepted, unless dirtyValues is
+// reject, and values which it would drop only produce a warning.
+func validateDocument(documentId string, document map[string]interface{}, fields []api.Field, partial bool, dirtyValues string, keyPath func(key string) path.Path, documentPath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if id, ok := document["id"]; ok {
+		if _, isString := id.(string); !isString {
+			diags.AddAttributeError(keyPath("id"), "Invalid Document", fmt.Sprintf("Document %s: the id of a document must be a string, got %v.", documentId, id))
+		}
+	}
+
+	for _, field := range fields {
+		if strings.Contains(field.Name, "*") {
+			continue
+		}
+
+		value, ok := documentFieldValue(document, field.Name)
+		optional := field.Optional != nil && *field.Optional
+
+		if !ok || value == nil {
+			if !optional && !partial && field.Embed == nil {
+				diags.AddAttributeError(documentPath, "Missing Document Field", fmt.Sprintf("Document %s: the field %s of type %s is not optional, but missing in the document.", documentId, field.Name, field.Type))
+			}
+
+			continue
+		}
+
+		if documentValueMatchesType(value, field.Type) {
+			continue
+		}
+
+		switch {
+		case dirtyValues == "drop" || dirtyValues == "coerce_or_drop":
+			if dirtyValues == "coerce_or_drop" && documentValueCoercesToType(value, field.Type) {
+				continue
+			}
+
+			diags.AddAttributeWarning(keyPath(field.Name), "Invalid Document Field", fmt.Sprintf("Document %s: the value of %s does not match the field type %s and will be dropped.", documentId, field.Name, field.Type))
+		case dirtyValues != "reject" && documentValueCoercesToType(value, field.Type):
+			continue
+		default:
+			diags.AddAttributeError(keyPath(field.Name), "Invalid Document Field", fmt.Sprintf("Document %s: the value of %s does not match the field type %s.", documentId, field.Name, field.Type))
+		}
+	}
+
+	return diags
+}
+
+// documentFieldValue returns the value of a field of the document. Names of
+// nested fields, e.g. address.city, are resolved against nested objects, the
+// values of a field nested in an array of objects are returned as an array.
+func documentFieldValue(document map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := document[name]; ok {
+		return value, true
+	}
+
+	parent, child, nested := strings.Cut(name, ".")
+
+	if !nested {
+		return nil, false
+	}
+
+	switch value := document[parent].(type) {
+	case map[string]interface{}:
+		return documentFieldValue(value, child)
+	case []interface{}:
+		values := []interface{}{}
+
+		for _, element := range value {
+			object, ok := element.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			if elementValue, ok := documentFieldValue(object, child); ok && elementValue != nil {
+				if elementValues, isArray := elementValue.([]interface{}); isArray {
+					values = append(values, elementValues...)
+				} else {
+					values = append(values, elementValue)
+				}
+			}
+		}
+
+		if len(values) == 0 {
+			return nil, false
+		}
+
+		return values, true
+	}
+
+	return nil, false
+}
+
+// documentValueMatchesType reports whether value is valid for a field of the
+// given type. Types which accept any value, like auto, always match.
+func documentValueMatchesType(value interface{}, fieldType string) bool {
+	if elementType, ok := strings.CutSuffix(fieldType, "[]"); ok && fieldType != "geopoint" {
+		elements, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+
+		for _, element := range elements {
+			if !documentValueMatchesType(element, elementType) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	switch fieldType {
+	case "string", "image":
+		_, ok := value.(string)
+		return ok
+	case "int32", "int64":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+
+		integer, err := number.Int64()
+		if err != nil {
+			return false
+		}
+
+		return fieldType == "int64" || (integer >= math.MinInt32 && integer <= math.MaxInt32)
+	case "float":
+		_, ok := value.(json.Number)
+		return ok
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "geopoint":
+		point, ok := value.([]interface{})
+		return ok && len(point) == 2 && documentValueMatchesType(point[0], "float") && documentValueMatchesType(point[1], "float")
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+
+	return true
+}
+
+// documentValueCoercesToType reports whether the server coerces value to the
+// given type, e.g. the string "5" to an int32 field.
+func documentValueCoercesToType(value interface{}, fieldType string) bool {
+	if elementType, ok := strings.CutSuffix(fieldType, "[]"); ok && fieldType != "geopoint" {
+		elements, ok := value.([]interface{})
+		if !ok {
+			// a single value is wrapped into an array
+			return documentValueMatchesType(value, elementType) || documentValueCoercesToType(value, elementType)
+		}
+
+		for _, element := range elements {
+			if !documentValueMatchesType(element, elementType) && !documentValueCoercesToType(element, elementType) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	switch fieldType {
+	case "string":
+		switch value.(type) {
+		case json.Number, bool:
+			return true
+		}
+	case "int32", "int64", "float":
+		if text, ok := value.(string); ok {
+			if fieldType == "float" {
+				return isNumber(text)
+			}
+
+			return documentValueMatchesType(json.Number(text), fieldType)
+		}
+
+		if number, ok := value.(json.Number); ok && fieldType != "float" {
+			// floats are truncated to integers which fit the field
+			float, err := number.Float64()
+			if err != nil {
+				return false
+			}
+
+			if fieldType == "int32" {
+				return float >= math.MinInt32 && float <= math.MaxInt32
+			}
+
+			return float >= math.MinInt64 && float <= math.MaxInt64
+		}
+
+		_, ok := value.(bool)
+		return ok
+	case "bool":
+		text, ok := value.(string)
+		return ok && (text == "true" || text == "false")
+	}
+
+	return false
+}
+
+func isNumber(text string) bool {
+	_, err := strconv.ParseFloat(text, 64)
+	return err == nil
 }
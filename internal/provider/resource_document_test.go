@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/typesense/typesense-go/typesense/api"
+)
+
+func TestValidateDocument(t *testing.T) {
+	optional := true
+
+	fields := []api.Field{
+		{Name: "title", Type: "string"},
+		{Name: "price", Type: "float"},
+		{Name: "stock", Type: "int32", Optional: &optional},
+		{Name: "tags", Type: "string[]", Optional: &optional},
+		{Name: "location", Type: "geopoint", Optional: &optional},
+		{Name: ".*_facet", Type: "auto"},
+	}
+
+	cases := []struct {
+		name        string
+		document    string
+		partial     bool
+		dirtyValues string
+		errors      int
+		warnings    int
+	}{
+		{name: "valid", document: `{"title":"shoe","price":12.5,"stock":3,"tags":["a"],"location":[52.5,13.4]}`},
+		{name: "missing required", document: `{"title":"shoe"}`, errors: 1},
+		{name: "missing required partial", document: `{"title":"shoe"}`, partial: true},
+		{name: "wrong type", document: `{"title":"shoe","price":true,"tags":"a"}`, dirtyValues: "reject", errors: 2},
+		{name: "coerced", document: `{"title":5,"price":"12.5","stock":"3"}`},
+		{name: "coerce rejected", document: `{"title":5,"price":"12.5"}`, dirtyValues: "reject", errors: 2},
+		{name: "int32 overflow", document: `{"title":"shoe","price":1,"stock":9007199254740993}`, errors: 1},
+		{name: "dropped", document: `{"title":"shoe","price":1,"location":"berlin"}`, dirtyValues: "drop", warnings: 1},
+		{name: "numeric id", document: `{"id":5,"title":"shoe","price":1}`, errors: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			document, err := parseJsonStringToMap(c.document)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			diags := validateDocument("test", document, fields, c.partial, c.dirtyValues, func(key string) path.Path {
+				return path.Root("fields").AtName(key)
+			}, path.Root("fields"))
+
+			if diags.ErrorsCount() != c.errors || diags.WarningsCount() != c.warnings {
+				t.Errorf("got %d errors and %d warnings, want %d and %d: %v", diags.ErrorsCount(), diags.WarningsCount(), c.errors, c.warnings, diags)
+			}
+		})
+	}
+}
+
+func TestReadFieldsKeepsDeclaredNulls(t *testing.T) {
+	declaredFields := types.ObjectValueMust(
+		map[string]attr.Type{"title": types.StringType, "owner": types.DynamicType},
+		map[string]attr.Value{"title": types.StringValue("shoe"), "owner": types.DynamicNull()},
+	)
+
+	for _, title := range []string{"shoe", "boot"} {
+		data := DocumentResourceModel{Fields: types.DynamicValue(declaredFields)}
+
+		declared, diags := declaredDocument(data)
+		if diags.HasError() {
+			t.Fatalf("unexpected errors: %v", diags)
+		}
+
+		// the server does not return the cleared owner
+		document := map[string]interface{}{"title": title}
+		keepDeclaredNulls(document, declared)
+
+		r := &DocumentResource{}
+
+		if diags := r.readFields(context.Background(), &data, document); diags.HasError() {
+			t.Fatalf("unexpected errors: %v", diags)
+		}
+
+		if title == "shoe" && !data.Fields.Equal(types.DynamicValue(declaredFields)) {
+			t.Errorf("fields = %s, want the declared fields %s", data.Fields, declaredFields)
+		}
+
+		object, ok := data.Fields.UnderlyingValue().(types.Object)
+		if !ok {
+			t.Fatalf("fields = %s, want an object", data.Fields)
+		}
+
+		if owner, ok := object.Attributes()["owner"]; !ok || !owner.IsNull() {
+			t.Errorf("fields = %s, want a null owner", data.Fields)
+		}
+
+		if _, err := data.Fields.ToTerraformValue(context.Background()); err != nil {
+			t.Errorf("unable to convert fields to a terraform value: %s", err)
+		}
+	}
+}
+
+func TestValidateDocumentNestedFields(t *testing.T) {
+	optional := true
+
+	fields := []api.Field{
+		{Name: "address", Type: "object"},
+		{Name: "address.city", Type: "string"},
+		{Name: "address.zip", Type: "int32", Optional: &optional},
+		{Name: "variants", Type: "object[]", Optional: &optional},
+		{Name: "variants.sku", Type: "string[]", Optional: &optional},
+	}
+
+	cases := []struct {
+		name     string
+		document string
+		errors   int
+	}{
+		{name: "valid", document: `{"address":{"city":"Berlin","zip":10115},"variants":[{"sku":"a"},{"sku":"b"}]}`},
+		{name: "missing nested", document: `{"address":{"zip":10115}}`, errors: 1},
+		{name: "wrong nested type", document: `{"address":{"city":["Berlin"]},"variants":[{"sku":{"id":1}}]}`, errors: 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			document, err := parseJsonStringToMap(c.document)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			diags := validateDocument("test", document, fields, false, "reject", func(key string) path.Path {
+				return path.Root("fields").AtName(key)
+			}, path.Root("fields"))
+
+			if diags.ErrorsCount() != c.errors {
+				t.Errorf("got %d errors, want %d: %v", diags.ErrorsCount(), c.errors, diags)
+			}
+		})
+	}
+}
+
+func TestValueFullyKnownWithNestedUnknown(t *testing.T) {
+	fields := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{"title": types.StringType, "owner": types.StringType},
+		map[string]attr.Value{"title": types.StringValue("shoe"), "owner": types.StringUnknown()},
+	))
+
+	if valueFullyKnown(fields) {
+		t.Error("fields with an unknown owner are reported as known")
+	}
+
+	if !valueFullyKnown(types.DynamicNull()) {
+		t.Error("null fields are reported as unknown")
+	}
+
+	if valueFullyKnown(types.DynamicUnknown()) {
+		t.Error("unknown fields are reported as known")
+	}
+
+	// the document can not be converted until the owner is known
+	if _, diags := declaredDocument(DocumentResourceModel{Fields: fields}); !diags.HasError() {
+		t.Error("expected an error converting fields with an unknown owner")
+	}
+}
@@ -0,0 +1,726 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/typesense/typesense-go/typesense/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &DocumentsResource{}
+var _ resource.ResourceWithConfigValidators = &DocumentsResource{}
+var _ resource.ResourceWithModifyPlan = &DocumentsResource{}
+
+// documentsFilterChunkSize is the number of document ids put into a single
+// filter_by expression when documents are exported or deleted.
+const documentsFilterChunkSize = 100
+
+func NewDocumentsResource() resource.Resource {
+	return &DocumentsResource{}
+}
+
+type DocumentsResource struct {
+	client *TypesenseClient
+}
+
+type DocumentsResourceModel struct {
+	Id             types.String           `tfsdk:"id"`
+	CollectionName types.String           `tfsdk:"collection_name"`
+	Documents      []jsontypes.Normalized `tfsdk:"documents"`
+	DocumentsFile  types.String           `tfsdk:"documents_file"`
+	Action         types.String           `tfsdk:"action"`
+	BatchSize      types.Int64            `tfsdk:"batch_size"`
+	DocumentHashes types.Map              `tfsdk:"document_hashes"`
+}
+
+func (r *DocumentsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_documents"
+}
+
+func (r *DocumentsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a set of documents of a collection with the import API. Only documents whose content changed are sent to the server and documents removed from the set are deleted",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Id identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "Collection name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"documents": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         jsontypes.NormalizedType{},
+				MarkdownDescription: "Documents in JSON format, every document needs an id without backticks. The documents are part of the configuration and kept in state, use `documents_file` for large documents",
+			},
+			"documents_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path to the documents, every document needs an id without backticks. Either a JSONL file with one document per line, a JSON file with a document or an array of documents, a directory whose .json and .jsonl files are read or a glob pattern matching such files. Only the hashes of the documents are kept in state",
+			},
+			"action": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Import action used to write changed documents, one of create, upsert or update. Documents which already exist are written with upsert when the action is create",
+				Default:             stringdefault.StaticString("upsert"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("create", "upsert", "update"),
+				},
+			},
+			"batch_size": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Number of documents sent with every import request",
+				Default:             int64default.StaticInt64(40),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"document_hashes": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "SHA-256 hashes of the applied documents by document id, used to send only changed documents",
+			},
+		},
+	}
+}
+
+func (r *DocumentsResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("documents"),
+			path.MatchRoot("documents_file"),
+		),
+	}
+}
+
+func (r *DocumentsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*TypesenseClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *DocumentsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var documents types.List
+	var documentsFile types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("documents"), &documents)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("documents_file"), &documentsFile)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if documents.IsUnknown() || documentsFile.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("document_hashes"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	declared := []jsontypes.Normalized{}
+
+	for _, element := range documents.Elements() {
+		document, ok := element.(jsontypes.Normalized)
+
+		if !ok || document.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("document_hashes"), types.MapUnknown(types.StringType))...)
+			return
+		}
+
+		declared = append(declared, document)
+	}
+
+	loaded, diags := loadDocuments(declared, documentsFile.ValueString())
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hashes, diags := hashDocuments(loaded.documents)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("document_hashes"), hashes)...)
+
+	var collectionName types.String
+	var action types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("collection_name"), &collectionName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("action"), &action)...)
+
+	if resp.Diagnostics.HasError() || r.client == nil || collectionName.IsUnknown() {
+		return
+	}
+
+	collection, err := r.client.retrieveCollection(ctx, collectionName.ValueString())
+
+	if err != nil {
+		// The collection may be created in the same apply
+		if !strings.Contains(err.Error(), "Not Found") {
+			resp.Diagnostics.AddWarning("Documents Not Validated", fmt.Sprintf("Unable to retrieve collection %s, got error: %s", collectionName.ValueString(), err))
+		}
+
+		return
+	}
+
+	for _, id := range loaded.ids {
+		documentPath := loaded.paths[id]
+
+		resp.Diagnostics.Append(validateDocument(id, loaded.documents[id], collection.Fields, action.ValueString() == "update", "", func(key string) path.Path {
+			return documentPath
+		}, documentPath)...)
+	}
+}
+
+func (r *DocumentsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DocumentsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	loaded, diags := loadDocuments(data.Documents, data.DocumentsFile.ValueString())
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hashes, diags := hashDocuments(loaded.documents)
+	resp.Diagnostics.Append(diags...)
+
+	applied := make(map[string]string)
+
+	failed := r.importDocuments(ctx, data, loaded, loaded.ids, nil, &resp.Diagnostics)
+
+	for _, id := range loaded.ids {
+		if !failed[id] {
+			applied[id] = hashes[id]
+		}
+	}
+
+	data.Id = types.StringValue(data.CollectionName.ValueString())
+	data.DocumentHashes, diags = types.MapValueFrom(ctx, types.StringType, applied)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DocumentsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DocumentsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied := make(map[string]string)
+	resp.Diagnostics.Append(data.DocumentHashes.ElementsAs(ctx, &applied, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The applied documents are used to compare only the declared keys, a
+	// missing documents file is not an error as the plan reports it.
+	loaded, diags := loadDocuments(data.Documents, data.DocumentsFile.ValueString())
+	if diags.HasError() {
+		tflog.Warn(ctx, "###Unable to load the declared documents, comparing whole documents")
+		loaded = &loadedDocuments{documents: map[string]map[string]interface{}{}}
+	}
+
+	ids := make([]string, 0, len(applied))
+	for id := range applied {
+		ids = append(ids, id)
+	}
+
+	current := make(map[string]string)
+
+	for _, chunk := range chunkStrings(ids, documentsFilterChunkSize) {
+		documents, err := r.client.exportDocuments(ctx, data.CollectionName.ValueString(), idFilter(chunk))
+
+		if err != nil {
+			if strings.Contains(err.Error(), "Not Found") {
+				resp.State.RemoveResource(ctx)
+				resp.Diagnostics.AddWarning("Resource Not Found", fmt.Sprintf("Unable to find collection %s, removing documents from state", data.CollectionName.ValueString()))
+			} else {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to export documents, got error: %s", err))
+			}
+
+			return
+		}
+
+		for _, document := range documents {
+			id, _ := document["id"].(string)
+
+			if declared, ok := loaded.documents[id]; ok {
+				document = projectDocument(document, declared)
+			}
+
+			hash, err := documentHash(document)
+			if err != nil {
+				resp.Diagnostics.AddError("JSON format error", fmt.Sprintf("Unable to hash document %s, got error: %s", id, err))
+				return
+			}
+
+			current[id] = hash
+		}
+	}
+
+	data.DocumentHashes, diags = types.MapValueFrom(ctx, types.StringType, current)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DocumentsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan DocumentsResourceModel
+	var state DocumentsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied := make(map[string]string)
+	resp.Diagnostics.Append(state.DocumentHashes.ElementsAs(ctx, &applied, false)...)
+
+	loaded, diags := loadDocuments(plan.Documents, plan.DocumentsFile.ValueString())
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hashes, diags := hashDocuments(loaded.documents)
+	resp.Diagnostics.Append(diags...)
+
+	changed := []string{}
+
+	for _, id := range loaded.ids {
+		if applied[id] != hashes[id] {
+			changed = append(changed, id)
+		}
+	}
+
+	removed := []string{}
+
+	for id := range applied {
+		if _, ok := loaded.documents[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("###Documents will be written: %d, deleted: %d", len(changed), len(removed)))
+
+	failed := r.importDocuments(ctx, plan, loaded, changed, applied, &resp.Diagnostics)
+
+	for _, id := range changed {
+		if !failed[id] {
+			applied[id] = hashes[id]
+		}
+	}
+
+	for _, chunk := range chunkStrings(removed, documentsFilterChunkSize) {
+		filter := idFilter(chunk)
+
+		_, err := r.client.Collection(plan.CollectionName.ValueString()).Documents().Delete(ctx, &api.DeleteDocumentsParams{FilterBy: &filter})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete documents, got error: %s", err))
+			break
+		}
+
+		for _, id := range chunk {
+			delete(applied, id)
+		}
+	}
+
+	plan.Id = types.StringValue(state.Id.ValueString())
+	plan.DocumentHashes, diags = types.MapValueFrom(ctx, types.StringType, applied)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *DocumentsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data DocumentsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied := make(map[string]string)
+	resp.Diagnostics.Append(data.DocumentHashes.ElementsAs(ctx, &applied, false)...)
+
+	ids := make([]string, 0, len(applied))
+	for id := range applied {
+		ids = append(ids, id)
+	}
+
+	tflog.Warn(ctx, fmt.Sprintf("###Delete %d documents of collection %s", len(ids), data.CollectionName.ValueString()))
+
+	for _, chunk := range chunkStrings(ids, documentsFilterChunkSize) {
+		filter := idFilter(chunk)
+
+		_, err := r.client.Collection(data.CollectionName.ValueString()).Documents().Delete(ctx, &api.DeleteDocumentsParams{FilterBy: &filter})
+
+		if err != nil {
+			if !strings.Contains(err.Error(), "Not Found") {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete documents, got error: %s", err))
+			}
+
+			return
+		}
+	}
+}
+
+// importDocuments writes the documents with the given ids in batches and
+// reports every document which failed to import. Documents present in
+// existing are written with upsert when the configured action is create.
+func (r *DocumentsResource) importDocuments(ctx context.Context, data DocumentsResourceModel, loaded *loadedDocuments, ids []string, existing map[string]string, diags *diag.Diagnostics) map[string]bool {
+	failed := make(map[string]bool)
+
+	byAction := make(map[string][]string)
+
+	for _, id := range ids {
+		action := data.Action.ValueString()
+
+		if _, ok := existing[id]; ok && action == "create" {
+			action = "upsert"
+		}
+
+		byAction[action] = append(byAction[action], id)
+	}
+
+	batchSize := int(data.BatchSize.ValueInt64())
+
+	// new documents are created before existing ones are upserted, in the
+	// same order on every apply
+	actions := []string{data.Action.ValueString()}
+
+	if data.Action.ValueString() == "create" {
+		actions = append(actions, "upsert")
+	}
+
+	for _, action := range actions {
+		for _, chunk := range chunkStrings(byAction[action], batchSize) {
+			documents := make([]interface{}, len(chunk))
+
+			for i, id := range chunk {
+				documents[i] = loaded.documents[id]
+			}
+
+			action := action
+
+			results, err := r.client.bulk.Collection(data.CollectionName.ValueString()).Documents().Import(ctx, documents, &api.ImportDocumentsParams{
+				Action:    &action,
+				BatchSize: &batchSize,
+			})
+
+			if err != nil {
+				diags.AddError("Client Error", fmt.Sprintf("Unable to import documents, got error: %s", err))
+
+				for _, id := range chunk {
+					failed[id] = true
+				}
+
+				continue
+			}
+
+			for i, result := range results {
+				if i < len(chunk) && !result.Success {
+					failed[chunk[i]] = true
+					diags.AddError("Import Error", fmt.Sprintf("Unable to %s document %s, line %d of the import failed with: %s", action, chunk[i], i+1, result.Error))
+				}
+			}
+		}
+	}
+
+	return failed
+}
+
+// loadedDocuments holds declared documents by id, ids keeps the declaration order.
+type loadedDocuments struct {
+	ids       []string
+	documents map[string]map[string]interface{}
+	paths     map[string]path.Path
+}
+
+// loadDocuments parses the declared documents, either given inline or as a
+// JSONL file, and checks that every document has a unique id.
+func loadDocuments(documents []jsontypes.Normalized, documentsFile string) (*loadedDocuments, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	loaded := &loadedDocuments{documents: make(map[string]map[string]interface{}), paths: make(map[string]path.Path)}
+
+	add := func(source string, attributePath path.Path, document map[string]interface{}) {
+		id, ok := document["id"].(string)
+
+		if !ok || id == "" {
+			diags.AddAttributeError(attributePath, "Invalid Document", fmt.Sprintf("Document %s needs a string id.", source))
+			return
+		}
+
+		// ids are quoted with backticks when documents are matched by id
+		if strings.Contains(id, "`") {
+			diags.AddAttributeError(attributePath, "Invalid Document", fmt.Sprintf("Document %s has the id %s, which must not contain a backtick.", source, id))
+			return
+		}
+
+		if _, ok := loaded.documents[id]; ok {
+			diags.AddAttributeError(attributePath, "Invalid Document", fmt.Sprintf("Document %s has the id %s, which is used more than once.", source, id))
+			return
+		}
+
+		loaded.ids = append(loaded.ids, id)
+		loaded.documents[id] = document
+		loaded.paths[id] = attributePath
+	}
+
+	for i, document := range documents {
+		attributePath := path.Root("documents").AtListIndex(i)
+
+		parsed, err := parseJsonStringToMap(document.ValueString())
+
+		if err != nil {
+			diags.AddAttributeError(attributePath, "JSON format error", fmt.Sprintf("Unable to parse document %d, got error: %s", i, err))
+			continue
+		}
+
+		add(fmt.Sprintf("%d", i), attributePath, parsed)
+	}
+
+	if documentsFile == "" {
+		return loaded, diags
+	}
+
+	files, err := documentFiles(documentsFile)
+
+	if err != nil {
+		diags.AddAttributeError(path.Root("documents_file"), "Invalid Documents File", fmt.Sprintf("Unable to list documents files, got error: %s", err))
+		return loaded, diags
+	}
+
+	for _, name := range files {
+		if strings.EqualFold(filepath.Ext(name), ".json") {
+			documents, err := readJsonDocuments(name)
+
+			if err != nil {
+				diags.AddAttributeError(path.Root("documents_file"), "JSON format error", fmt.Sprintf("Unable to parse %s, got error: %s", name, err))
+				continue
+			}
+
+			for i, document := range documents {
+				add(fmt.Sprintf("%d of %s", i, name), path.Root("documents_file"), document)
+			}
+
+			continue
+		}
+
+		diags.Append(readJsonlDocuments(name, add)...)
+	}
+
+	return loaded, diags
+}
+
+// documentFiles returns the files matched by pattern, which is either a
+// single file, a directory whose .json and .jsonl files are used or a glob
+// pattern. Files are returned in lexical order.
+func documentFiles(pattern string) ([]string, error) {
+	info, err := os.Stat(pattern)
+
+	if err == nil && !info.IsDir() {
+		return []string{pattern}, nil
+	}
+
+	patterns := []string{pattern}
+
+	if err == nil {
+		patterns = []string{filepath.Join(pattern, "*.json"), filepath.Join(pattern, "*.jsonl")}
+	}
+
+	files := []string{}
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, matches...)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// readJsonDocuments reads a JSON file holding either a single document or an
+// array of documents.
+func readJsonDocuments(name string) ([]map[string]interface{}, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	content = bytes.TrimSpace(content)
+
+	if len(content) > 0 && content[0] == '[' {
+		var documents []map[string]interface{}
+		err = unmarshalJson(content, &documents)
+
+		return documents, err
+	}
+
+	var document map[string]interface{}
+
+	if err := unmarshalJson(content, &document); err != nil {
+		return nil, err
+	}
+
+	return []map[string]interface{}{document}, nil
+}
+
+// readJsonlDocuments reads a JSONL file with one document per line and passes
+// every document to add.
+func readJsonlDocuments(name string, add func(source string, attributePath path.Path, document map[string]interface{})) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	file, err := os.Open(name)
+
+	if err != nil {
+		diags.AddAttributeError(path.Root("documents_file"), "Invalid Documents File", fmt.Sprintf("Unable to open documents file, got error: %s", err))
+		return diags
+	}
+
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	for lineNumber := 1; ; lineNumber += 1 {
+		line, readErr := reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+
+		if len(line) > 0 {
+			var document map[string]interface{}
+
+			if err := unmarshalJson(line, &document); err != nil {
+				diags.AddAttributeError(path.Root("documents_file"), "JSON format error", fmt.Sprintf("Unable to parse line %d of %s, got error: %s", lineNumber, name, err))
+			} else {
+				add(fmt.Sprintf("on line %d of %s", lineNumber, name), path.Root("documents_file"), document)
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+
+		if readErr != nil {
+			diags.AddAttributeError(path.Root("documents_file"), "Invalid Documents File", fmt.Sprintf("Unable to read documents file, got error: %s", readErr))
+			break
+		}
+	}
+
+	return diags
+}
+
+// hashDocuments returns the hashes of the documents by id.
+func hashDocuments(documents map[string]map[string]interface{}) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	hashes := make(map[string]string, len(documents))
+
+	for id, document := range documents {
+		hash, err := documentHash(document)
+
+		if err != nil {
+			diags.AddError("JSON format error", fmt.Sprintf("Unable to hash document %s, got error: %s", id, err))
+			continue
+		}
+
+		hashes[id] = hash
+	}
+
+	return hashes, diags
+}
+
+// chunkStrings splits values into chunks of at most size elements.
+func chunkStrings(values []string, size int) [][]string {
+	chunks := [][]string{}
+
+	for size < len(values) {
+		chunks = append(chunks, values[:size])
+		values = values[size:]
+	}
+
+	if len(values) > 0 {
+		chunks = append(chunks, values)
+	}
+
+	return chunks
+}
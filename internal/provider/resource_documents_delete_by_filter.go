@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/typesense/typesense-go/typesense/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &DocumentsDeleteByFilterResource{}
+var _ resource.ResourceWithModifyPlan = &DocumentsDeleteByFilterResource{}
+
+func NewDocumentsDeleteByFilterResource() resource.Resource {
+	return &DocumentsDeleteByFilterResource{}
+}
+
+type DocumentsDeleteByFilterResource struct {
+	client *TypesenseClient
+}
+
+type DocumentsDeleteByFilterResourceModel struct {
+	Id             types.String `tfsdk:"id"`
+	CollectionName types.String `tfsdk:"collection_name"`
+	FilterBy       types.String `tfsdk:"filter_by"`
+	BatchSize      types.Int64  `tfsdk:"batch_size"`
+	RunOn          types.String `tfsdk:"run_on"`
+	DeletedCount   types.Int64  `tfsdk:"deleted_count"`
+	PreviewCount   types.Int64  `tfsdk:"preview_count"`
+}
+
+func (r *DocumentsDeleteByFilterResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_documents_delete_by_filter"
+}
+
+func (r *DocumentsDeleteByFilterResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deletes every document of a collection which matches a filter, either when the resource is created or when it is destroyed",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Id identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "Collection name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"filter_by": schema.StringAttribute{
+				MarkdownDescription: "Filter matching the documents to delete, e.g. `tenant_id:=acme`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"batch_size": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Number of documents deleted at a time by the server",
+				Default:             int64default.StaticInt64(100),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"run_on": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "When the documents are deleted, one of create or destroy",
+				Default:             stringdefault.StaticString("create"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("create", "destroy"),
+				},
+			},
+			"deleted_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of documents deleted on create, 0 when run_on is destroy",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"preview_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of documents matching the filter when the resource was planned",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *DocumentsDeleteByFilterResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*TypesenseClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *DocumentsDeleteByFilterResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Only preview the count when the resource is created
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var collectionName types.String
+	var filterBy types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("collection_name"), &collectionName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("filter_by"), &filterBy)...)
+
+	if resp.Diagnostics.HasError() || collectionName.IsUnknown() || filterBy.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(previewDocumentCount(ctx, r.client, collectionName.ValueString(), filterBy.ValueString(), resp)...)
+}
+
+func (r *DocumentsDeleteByFilterResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DocumentsDeleteByFilterResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(createId(data.CollectionName.ValueString(), data.FilterBy.ValueString()))
+	data.DeletedCount = types.Int64Value(0)
+
+	if data.PreviewCount.IsUnknown() {
+		data.PreviewCount = types.Int64Null()
+	}
+
+	if data.RunOn.ValueString() == "create" {
+		deleted, err := r.deleteDocuments(ctx, data)
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete documents, got error: %s", err))
+			return
+		}
+
+		data.DeletedCount = types.Int64Value(int64(deleted))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DocumentsDeleteByFilterResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DocumentsDeleteByFilterResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The deletion is a one off operation, there is nothing to refresh
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DocumentsDeleteByFilterResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data DocumentsDeleteByFilterResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only batch_size and run_on can change in place, they take effect on
+	// destroy
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DocumentsDeleteByFilterResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data DocumentsDeleteByFilterResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.RunOn.ValueString() != "destroy" {
+		return
+	}
+
+	deleted, err := r.deleteDocuments(ctx, data)
+
+	if err != nil {
+		if !strings.Contains(err.Error(), "Not Found") {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete documents, got error: %s", err))
+		}
+
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("###Deleted %d documents", deleted))
+}
+
+func (r *DocumentsDeleteByFilterResource) deleteDocuments(ctx context.Context, data DocumentsDeleteByFilterResourceModel) (int, error) {
+	filterBy := data.FilterBy.ValueString()
+	batchSize := int(data.BatchSize.ValueInt64())
+
+	tflog.Warn(ctx, fmt.Sprintf("###Delete documents of collection %s matching %s", data.CollectionName.ValueString(), filterBy))
+
+	return r.client.Collection(data.CollectionName.ValueString()).Documents().Delete(ctx, &api.DeleteDocumentsParams{
+		FilterBy:  &filterBy,
+		BatchSize: &batchSize,
+	})
+}
+
+// previewDocumentCount plans preview_count with the number of documents
+// matching the filter. The count stays unknown when the collection does not
+// exist yet, e.g. because it is created in the same apply.
+func previewDocumentCount(ctx context.Context, client *TypesenseClient, collectionName string, filterBy string, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	found, err := client.countDocuments(ctx, collectionName, filterBy)
+
+	if err != nil {
+		if !strings.Contains(err.Error(), "Not Found") {
+			diags.AddAttributeWarning(path.Root("filter_by"), "Preview Failed", fmt.Sprintf("Unable to count the documents matching the filter, got error: %s", err))
+		}
+
+		return diags
+	}
+
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("preview_count"), types.Int64Value(int64(found)))...)
+
+	return diags
+}
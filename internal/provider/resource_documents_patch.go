@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/typesense/typesense-go/typesense/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &DocumentsPatchResource{}
+var _ resource.ResourceWithModifyPlan = &DocumentsPatchResource{}
+
+func NewDocumentsPatchResource() resource.Resource {
+	return &DocumentsPatchResource{}
+}
+
+type DocumentsPatchResource struct {
+	client *TypesenseClient
+}
+
+type DocumentsPatchResourceModel struct {
+	Id             types.String         `tfsdk:"id"`
+	CollectionName types.String         `tfsdk:"collection_name"`
+	FilterBy       types.String         `tfsdk:"filter_by"`
+	Patch          jsontypes.Normalized `tfsdk:"patch"`
+	UpdatedCount   types.Int64          `tfsdk:"updated_count"`
+	PreviewCount   types.Int64          `tfsdk:"preview_count"`
+}
+
+func (r *DocumentsPatchResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_documents_patch"
+}
+
+func (r *DocumentsPatchResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Applies a partial patch to every document of a collection which matches a filter. The patch is applied again whenever the patch or the filter changes, destroying the resource does not revert it",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Id identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "Collection name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"filter_by": schema.StringAttribute{
+				MarkdownDescription: "Filter matching the documents to patch, e.g. `category:=shoes`. Changing the filter replaces the resource, which applies the patch to the documents matching the new filter",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"patch": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Partial document in JSON format with the fields to set on every matching document",
+				CustomType:          jsontypes.NormalizedType{},
+			},
+			"updated_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of documents updated when the patch was last applied",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"preview_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of documents matching the filter when the patch was planned",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *DocumentsPatchResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*TypesenseClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *DocumentsPatchResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan DocumentsPatchResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.Patch.IsUnknown() {
+		return
+	}
+
+	patch, err := parseJsonStringToMap(plan.Patch.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("patch"), "JSON format error", fmt.Sprintf("Unable to parse patch json, got error: %s", err))
+		return
+	}
+
+	if _, ok := patch["id"]; ok {
+		resp.Diagnostics.AddAttributeError(path.Root("patch"), "Invalid Patch", "The patch must not contain an id, the id of a document can not be changed.")
+		return
+	}
+
+	// Preview only when the patch is applied with this plan, otherwise the
+	// counts of the last apply are kept
+	if !req.State.Raw.IsNull() {
+		var state DocumentsPatchResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() || (state.FilterBy.Equal(plan.FilterBy) && state.Patch.Equal(plan.Patch) && state.CollectionName.Equal(plan.CollectionName)) {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("updated_count"), types.Int64Unknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("preview_count"), types.Int64Unknown())...)
+	}
+
+	if r.client == nil || plan.CollectionName.IsUnknown() || plan.FilterBy.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(previewDocumentCount(ctx, r.client, plan.CollectionName.ValueString(), plan.FilterBy.ValueString(), resp)...)
+}
+
+func (r *DocumentsPatchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DocumentsPatchResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(createId(data.CollectionName.ValueString(), data.FilterBy.ValueString()))
+
+	r.applyPatch(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DocumentsPatchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DocumentsPatchResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The patch is applied once per change, there is nothing to refresh
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DocumentsPatchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data DocumentsPatchResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.applyPatch(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DocumentsPatchResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data DocumentsPatchResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "###Patched documents are kept as they are, id="+data.Id.ValueString())
+}
+
+func (r *DocumentsPatchResource) applyPatch(ctx context.Context, data *DocumentsPatchResourceModel, diags *diag.Diagnostics) {
+	patch, err := parseJsonStringToMap(data.Patch.ValueString())
+
+	if err != nil {
+		diags.AddError("JSON format error", fmt.Sprintf("Unable to parse patch json, got error: %s", err))
+		return
+	}
+
+	filterBy := data.FilterBy.ValueString()
+
+	tflog.Info(ctx, fmt.Sprintf("###Patch documents of collection %s matching %s", data.CollectionName.ValueString(), filterBy))
+
+	updated, err := r.client.Collection(data.CollectionName.ValueString()).Documents().Update(ctx, patch, &api.UpdateDocumentsParams{FilterBy: &filterBy})
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to update documents, got error: %s", err))
+		return
+	}
+
+	data.UpdatedCount = types.Int64Value(int64(updated))
+
+	if data.PreviewCount.IsUnknown() {
+		data.PreviewCount = types.Int64Null()
+	}
+}
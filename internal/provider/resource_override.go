@@ -0,0 +1,549 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OverrideResource{}
+var _ resource.ResourceWithImportState = &OverrideResource{}
+var _ resource.ResourceWithModifyPlan = &OverrideResource{}
+var _ resource.ResourceWithValidateConfig = &OverrideResource{}
+
+func NewOverrideResource() resource.Resource {
+	return &OverrideResource{}
+}
+
+type OverrideResource struct {
+	client *TypesenseClient
+}
+
+type OverrideResourceModel struct {
+	Id                  types.String           `tfsdk:"id"`
+	Name                types.String           `tfsdk:"name"`
+	CollectionName      types.String           `tfsdk:"collection_name"`
+	Rule                []OverrideRuleModel    `tfsdk:"rule"`
+	Includes            []OverrideIncludeModel `tfsdk:"includes"`
+	Excludes            []OverrideExcludeModel `tfsdk:"excludes"`
+	FilterBy            types.String           `tfsdk:"filter_by"`
+	SortBy              types.String           `tfsdk:"sort_by"`
+	ReplaceQuery        types.String           `tfsdk:"replace_query"`
+	RemoveMatchedTokens types.Bool             `tfsdk:"remove_matched_tokens"`
+	FilterCuratedHits   types.Bool             `tfsdk:"filter_curated_hits"`
+	EffectiveFromTs     types.Int64            `tfsdk:"effective_from_ts"`
+	EffectiveToTs       types.Int64            `tfsdk:"effective_to_ts"`
+	StopProcessing      types.Bool             `tfsdk:"stop_processing"`
+	Metadata            jsontypes.Normalized   `tfsdk:"metadata"`
+}
+
+type OverrideRuleModel struct {
+	Query    types.String `tfsdk:"query"`
+	Match    types.String `tfsdk:"match"`
+	Tags     types.Set    `tfsdk:"tags"`
+	FilterBy types.String `tfsdk:"filter_by"`
+}
+
+type OverrideIncludeModel struct {
+	Id       types.String `tfsdk:"id"`
+	Position types.Int64  `tfsdk:"position"`
+}
+
+type OverrideExcludeModel struct {
+	Id types.String `tfsdk:"id"`
+}
+
+func (r *OverrideResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_override"
+}
+
+func (r *OverrideResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Overrides, also known as curation, promote or exclude certain documents for searches matching a rule, and can change the filter, the sort order or the query of those searches",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Id identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name identifier",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "Collection name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"filter_by": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Filter applied to searches matching the rule. Placeholders like `{category}` are replaced with the tokens matched by the rule query",
+			},
+			"sort_by": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Sort order applied to searches matching the rule",
+			},
+			"replace_query": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Query which replaces the query of searches matching the rule",
+			},
+			"remove_matched_tokens": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Remove the tokens matched by the rule query from the search query",
+				Default:             booldefault.StaticBool(true),
+			},
+			"filter_curated_hits": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Apply the filters of the search to the included documents as well",
+				Default:             booldefault.StaticBool(false),
+			},
+			"effective_from_ts": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Unix timestamp from which the override is applied",
+			},
+			"effective_to_ts": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Unix timestamp until which the override is applied",
+			},
+			"stop_processing": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Stop processing further overrides when this override matches. Overrides are processed in the lexical order of their names",
+				Default:             booldefault.StaticBool(true),
+			},
+			"metadata": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Custom JSON object which is returned with the search results when the override matches",
+				CustomType:          jsontypes.NormalizedType{},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rule": schema.ListNestedBlock{
+				MarkdownDescription: "Rule matching the searches the override is applied to. Either query and match, tags or filter_by must be set",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"query": schema.StringAttribute{
+							Optional:    true,
+							Description: "Query matched by the rule, may contain placeholders like {category}",
+						},
+						"match": schema.StringAttribute{
+							Optional:    true,
+							Description: "Whether the query must match exactly or must be contained in the search query, one of exact or contains",
+							Validators: []validator.String{
+								stringvalidator.OneOf("exact", "contains"),
+							},
+						},
+						"tags": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Tags matched by the rule, the override is applied to searches with these override_tags",
+							Validators:  []validator.Set{setvalidator.SizeAtLeast(1)},
+						},
+						"filter_by": schema.StringAttribute{
+							Optional:    true,
+							Description: "Filter matched by the rule, the override is applied to searches with this filter",
+						},
+					},
+				},
+				Validators: []validator.List{
+					listvalidator.SizeBetween(1, 1),
+				},
+			},
+			"includes": schema.ListNestedBlock{
+				MarkdownDescription: "Documents included at fixed positions",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Document id to include",
+						},
+						"position": schema.Int64Attribute{
+							Required:    true,
+							Description: "Position of the document in the search results, starting at 1",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+					},
+				},
+			},
+			"excludes": schema.ListNestedBlock{
+				MarkdownDescription: "Documents excluded from the search results",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Document id to exclude",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *OverrideResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*TypesenseClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OverrideResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var ruleList types.List
+	var effectiveFromTs types.Int64
+	var effectiveToTs types.Int64
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rule"), &ruleList)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("effective_from_ts"), &effectiveFromTs)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("effective_to_ts"), &effectiveToTs)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules := []OverrideRuleModel{}
+
+	if !ruleList.IsUnknown() {
+		resp.Diagnostics.Append(ruleList.ElementsAs(ctx, &rules, false)...)
+	}
+
+	for i, rule := range rules {
+		rulePath := path.Root("rule").AtListIndex(i)
+
+		if rule.Query.IsUnknown() || rule.Match.IsUnknown() || rule.Tags.IsUnknown() || rule.FilterBy.IsUnknown() {
+			continue
+		}
+
+		if rule.Query.IsNull() != rule.Match.IsNull() {
+			resp.Diagnostics.AddAttributeError(rulePath, "Invalid Rule", "query and match must be set together.")
+		}
+
+		if rule.Query.IsNull() && rule.Tags.IsNull() && rule.FilterBy.IsNull() {
+			resp.Diagnostics.AddAttributeError(rulePath, "Invalid Rule", "The rule needs query and match, tags or filter_by.")
+		}
+	}
+
+	if effectiveFromTs.IsNull() || effectiveFromTs.IsUnknown() || effectiveToTs.IsNull() || effectiveToTs.IsUnknown() {
+		return
+	}
+
+	if effectiveToTs.ValueInt64() < effectiveFromTs.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(path.Root("effective_to_ts"), "Invalid Attribute Value", "effective_to_ts must not be before effective_from_ts.")
+	}
+}
+
+func (r *OverrideResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is written to the server when the override is destroyed
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.client.requireServerVersionBefore(ctx, 30, "The per-collection overrides API", "Typesense v30 keeps overrides in curation sets, which are not supported by this resource.", path.Root("collection_name"))...)
+}
+
+func (r *OverrideResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OverrideResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schema, diags := expandOverrideSchema(ctx, data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("###Create override %s of collection %s", data.Name.ValueString(), data.CollectionName.ValueString()))
+
+	override, err := r.client.upsertOverride(ctx, data.CollectionName.ValueString(), data.Name.ValueString(), schema)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create override, got error: %s", err))
+		return
+	}
+
+	data.Id = types.StringValue(createId(data.CollectionName.ValueString(), override.Id))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OverrideResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OverrideResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	collectionName, id, err := splitCollectionRelatedId(data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unable to split resource ID: %s", err))
+		return
+	}
+
+	override, err := r.client.retrieveOverride(ctx, collectionName, id)
+
+	if err != nil {
+		if strings.Contains(err.Error(), "Not Found") {
+			resp.State.RemoveResource(ctx)
+			resp.Diagnostics.AddWarning("Resource Not Found", fmt.Sprintf("Unable to find override %s, removing from state", data.Id.ValueString()))
+		} else {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to retrieve override, got error: %s", err))
+		}
+
+		return
+	}
+
+	data.Name = types.StringValue(id)
+	data.CollectionName = types.StringValue(collectionName)
+
+	resp.Diagnostics.Append(flattenOverrideSchema(ctx, &data, override)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OverrideResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OverrideResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schema, diags := expandOverrideSchema(ctx, data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	collectionName, id, err := splitCollectionRelatedId(data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unable to split resource ID: %s", err))
+		return
+	}
+
+	_, err = r.client.upsertOverride(ctx, collectionName, id, schema)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update override, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OverrideResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OverrideResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Warn(ctx, "###Delete override with id="+data.Id.ValueString())
+
+	collectionName, id, err := splitCollectionRelatedId(data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unable to split resource ID: %s", err))
+		return
+	}
+
+	_, err = r.client.Collection(collectionName).Override(id).Delete(ctx)
+
+	if err != nil {
+		if strings.Contains(err.Error(), "Not Found") {
+			resp.State.RemoveResource(ctx)
+		} else {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete override, got error: %s", err))
+		}
+
+		return
+	}
+}
+
+func (r *OverrideResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	collectionName, name, err := splitCollectionRelatedId(req.ID)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unable to split resource ID: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), createId(collectionName, name))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("collection_name"), collectionName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+func expandOverrideSchema(ctx context.Context, data OverrideResourceModel) (*overrideSchema, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	schema := &overrideSchema{
+		FilterBy:            data.FilterBy.ValueString(),
+		SortBy:              data.SortBy.ValueString(),
+		ReplaceQuery:        data.ReplaceQuery.ValueString(),
+		RemoveMatchedTokens: data.RemoveMatchedTokens.ValueBoolPointer(),
+		FilterCuratedHits:   data.FilterCuratedHits.ValueBoolPointer(),
+		EffectiveFromTs:     data.EffectiveFromTs.ValueInt64Pointer(),
+		EffectiveToTs:       data.EffectiveToTs.ValueInt64Pointer(),
+		StopProcessing:      data.StopProcessing.ValueBoolPointer(),
+	}
+
+	if len(data.Rule) > 0 {
+		rule := data.Rule[0]
+
+		schema.Rule = overrideRule{
+			Query:    rule.Query.ValueString(),
+			Match:    rule.Match.ValueString(),
+			FilterBy: rule.FilterBy.ValueString(),
+		}
+
+		diags.Append(rule.Tags.ElementsAs(ctx, &schema.Rule.Tags, false)...)
+	}
+
+	for _, include := range data.Includes {
+		schema.Includes = append(schema.Includes, overrideInclude{
+			Id:       include.Id.ValueString(),
+			Position: include.Position.ValueInt64(),
+		})
+	}
+
+	for _, exclude := range data.Excludes {
+		schema.Excludes = append(schema.Excludes, overrideExclude{Id: exclude.Id.ValueString()})
+	}
+
+	if !data.Metadata.IsNull() {
+		var metadata map[string]interface{}
+
+		if err := unmarshalJson([]byte(data.Metadata.ValueString()), &metadata); err != nil {
+			diags.AddAttributeError(path.Root("metadata"), "JSON format error", fmt.Sprintf("Metadata must be a JSON object, got error: %s", err))
+			return nil, diags
+		}
+
+		schema.Metadata = json.RawMessage(data.Metadata.ValueString())
+	}
+
+	return schema, diags
+}
+
+// flattenOverrideSchema sets the override settings from the server, empty
+// values which the server returns for unset settings are kept null.
+func flattenOverrideSchema(ctx context.Context, data *OverrideResourceModel, override *overrideSchema) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rule := OverrideRuleModel{
+		Query:    stringValueOrNull(override.Rule.Query),
+		Match:    stringValueOrNull(override.Rule.Match),
+		Tags:     types.SetNull(types.StringType),
+		FilterBy: stringValueOrNull(override.Rule.FilterBy),
+	}
+
+	if len(override.Rule.Tags) > 0 {
+		var tagDiags diag.Diagnostics
+
+		rule.Tags, tagDiags = types.SetValueFrom(ctx, types.StringType, override.Rule.Tags)
+		diags.Append(tagDiags...)
+	}
+
+	data.Rule = []OverrideRuleModel{rule}
+
+	data.Includes = []OverrideIncludeModel{}
+	for _, include := range override.Includes {
+		data.Includes = append(data.Includes, OverrideIncludeModel{
+			Id:       types.StringValue(include.Id),
+			Position: types.Int64Value(include.Position),
+		})
+	}
+
+	data.Excludes = []OverrideExcludeModel{}
+	for _, exclude := range override.Excludes {
+		data.Excludes = append(data.Excludes, OverrideExcludeModel{Id: types.StringValue(exclude.Id)})
+	}
+
+	data.FilterBy = stringValueOrNull(override.FilterBy)
+	data.SortBy = stringValueOrNull(override.SortBy)
+	data.ReplaceQuery = stringValueOrNull(override.ReplaceQuery)
+	data.EffectiveFromTs = types.Int64PointerValue(override.EffectiveFromTs)
+	data.EffectiveToTs = types.Int64PointerValue(override.EffectiveToTs)
+
+	if override.RemoveMatchedTokens != nil {
+		data.RemoveMatchedTokens = types.BoolPointerValue(override.RemoveMatchedTokens)
+	}
+
+	if override.FilterCuratedHits != nil {
+		data.FilterCuratedHits = types.BoolPointerValue(override.FilterCuratedHits)
+	}
+
+	if override.StopProcessing != nil {
+		data.StopProcessing = types.BoolPointerValue(override.StopProcessing)
+	}
+
+	data.Metadata = jsontypes.NewNormalizedNull()
+
+	if len(override.Metadata) > 0 && string(override.Metadata) != "null" {
+		data.Metadata = jsontypes.NewNormalizedValue(string(override.Metadata))
+	}
+
+	return diags
+}
+
+func stringValueOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(value)
+}
@@ -0,0 +1,259 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newOverrideModel returns an override with the schema defaults set, as it is
+// planned when only the rule is configured
+func newOverrideModel(rule OverrideRuleModel) OverrideResourceModel {
+	return OverrideResourceModel{
+		Id:                  types.StringValue("products.promote"),
+		Name:                types.StringValue("promote"),
+		CollectionName:      types.StringValue("products"),
+		Rule:                []OverrideRuleModel{rule},
+		Includes:            []OverrideIncludeModel{},
+		Excludes:            []OverrideExcludeModel{},
+		RemoveMatchedTokens: types.BoolValue(true),
+		FilterCuratedHits:   types.BoolValue(false),
+		StopProcessing:      types.BoolValue(true),
+		Metadata:            jsontypes.NewNormalizedNull(),
+	}
+}
+
+func queryRule(query string, match string) OverrideRuleModel {
+	return OverrideRuleModel{
+		Query:    types.StringValue(query),
+		Match:    types.StringValue(match),
+		Tags:     types.SetNull(types.StringType),
+		FilterBy: types.StringNull(),
+	}
+}
+
+// roundTripOverride expands the model, sends it through the JSON encoding of
+// the API and flattens the response into a model planned with the defaults
+func roundTripOverride(t *testing.T, data OverrideResourceModel, response string) OverrideResourceModel {
+	t.Helper()
+
+	ctx := context.Background()
+
+	override, diags := expandOverrideSchema(ctx, data)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	body, err := json.Marshal(override)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if response == "" {
+		response = string(body)
+	}
+
+	var returned overrideSchema
+
+	if err := json.Unmarshal([]byte(response), &returned); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result := newOverrideModel(queryRule("", ""))
+	result.Id = data.Id
+	result.Name = data.Name
+	result.CollectionName = data.CollectionName
+	result.RemoveMatchedTokens = data.RemoveMatchedTokens
+	result.FilterCuratedHits = data.FilterCuratedHits
+	result.StopProcessing = data.StopProcessing
+
+	diags = flattenOverrideSchema(ctx, &result, &returned)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	return result
+}
+
+func TestOverrideSchemaRoundTrip(t *testing.T) {
+	tags, _ := types.SetValueFrom(context.Background(), types.StringType, []string{"sale", "summer"})
+
+	full := newOverrideModel(queryRule("{category} shoes", "contains"))
+	full.Includes = []OverrideIncludeModel{
+		{Id: types.StringValue("42"), Position: types.Int64Value(1)},
+		{Id: types.StringValue("7"), Position: types.Int64Value(2)},
+	}
+	full.Excludes = []OverrideExcludeModel{{Id: types.StringValue("13")}}
+	full.FilterBy = types.StringValue("category:={category}")
+	full.SortBy = types.StringValue("price:asc")
+	full.ReplaceQuery = types.StringValue("shoes")
+	full.RemoveMatchedTokens = types.BoolValue(false)
+	full.FilterCuratedHits = types.BoolValue(true)
+	full.EffectiveFromTs = types.Int64Value(1700000000)
+	full.EffectiveToTs = types.Int64Value(1800000000)
+	full.StopProcessing = types.BoolValue(false)
+	full.Metadata = jsontypes.NewNormalizedValue(`{"banner":"sale"}`)
+
+	tagged := newOverrideModel(OverrideRuleModel{
+		Query:    types.StringNull(),
+		Match:    types.StringNull(),
+		Tags:     tags,
+		FilterBy: types.StringNull(),
+	})
+	tagged.Excludes = []OverrideExcludeModel{{Id: types.StringValue("13")}}
+
+	filtered := newOverrideModel(OverrideRuleModel{
+		Query:    types.StringNull(),
+		Match:    types.StringNull(),
+		Tags:     types.SetNull(types.StringType),
+		FilterBy: types.StringValue("brand:=acme"),
+	})
+	filtered.SortBy = types.StringValue("rating:desc")
+
+	cases := []struct {
+		name string
+		data OverrideResourceModel
+	}{
+		{name: "query", data: newOverrideModel(queryRule("shoes", "exact"))},
+		{name: "all settings", data: full},
+		{name: "tags", data: tagged},
+		{name: "filter", data: filtered},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := roundTripOverride(t, c.data, "")
+
+			if !reflect.DeepEqual(got, c.data) {
+				t.Errorf("round trip = %+v, want %+v", got, c.data)
+			}
+		})
+	}
+}
+
+func TestOverrideSchemaDefaults(t *testing.T) {
+	cases := []struct {
+		name                string
+		response            string
+		removeMatchedTokens bool
+		filterCuratedHits   bool
+		stopProcessing      bool
+	}{
+		{
+			name:                "settings not returned",
+			response:            `{"id":"promote","rule":{"query":"shoes","match":"exact"}}`,
+			removeMatchedTokens: true,
+			stopProcessing:      true,
+		},
+		{
+			name:                "defaults returned",
+			response:            `{"id":"promote","rule":{"query":"shoes","match":"exact"},"remove_matched_tokens":true,"filter_curated_hits":false,"stop_processing":true}`,
+			removeMatchedTokens: true,
+			stopProcessing:      true,
+		},
+		{
+			name:              "changed outside terraform",
+			response:          `{"id":"promote","rule":{"query":"shoes","match":"exact"},"remove_matched_tokens":false,"filter_curated_hits":true,"stop_processing":false}`,
+			filterCuratedHits: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := roundTripOverride(t, newOverrideModel(queryRule("shoes", "exact")), c.response)
+
+			if got.RemoveMatchedTokens.ValueBool() != c.removeMatchedTokens {
+				t.Errorf("remove_matched_tokens = %v, want %v", got.RemoveMatchedTokens.ValueBool(), c.removeMatchedTokens)
+			}
+
+			if got.FilterCuratedHits.ValueBool() != c.filterCuratedHits {
+				t.Errorf("filter_curated_hits = %v, want %v", got.FilterCuratedHits.ValueBool(), c.filterCuratedHits)
+			}
+
+			if got.StopProcessing.ValueBool() != c.stopProcessing {
+				t.Errorf("stop_processing = %v, want %v", got.StopProcessing.ValueBool(), c.stopProcessing)
+			}
+
+			if !got.Metadata.IsNull() || !got.FilterBy.IsNull() || !got.EffectiveFromTs.IsNull() {
+				t.Errorf("unset settings are not null: %+v", got)
+			}
+		})
+	}
+
+	override, diags := expandOverrideSchema(context.Background(), newOverrideModel(queryRule("shoes", "exact")))
+
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if override.RemoveMatchedTokens == nil || !*override.RemoveMatchedTokens || override.StopProcessing == nil || !*override.StopProcessing {
+		t.Errorf("the defaults of remove_matched_tokens and stop_processing are not sent as true: %+v", override)
+	}
+}
+
+func TestOverrideValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	r := &OverrideResource{}
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	tags, _ := types.SetValueFrom(ctx, types.StringType, []string{"sale"})
+
+	cases := []struct {
+		name   string
+		rule   OverrideRuleModel
+		from   types.Int64
+		to     types.Int64
+		errors int
+	}{
+		{name: "query and match", rule: queryRule("shoes", "exact")},
+		{name: "tags", rule: OverrideRuleModel{Query: types.StringNull(), Match: types.StringNull(), Tags: tags, FilterBy: types.StringNull()}},
+		{name: "filter", rule: OverrideRuleModel{Query: types.StringNull(), Match: types.StringNull(), Tags: types.SetNull(types.StringType), FilterBy: types.StringValue("brand:=acme")}},
+		{name: "query without match", rule: OverrideRuleModel{Query: types.StringValue("shoes"), Match: types.StringNull(), Tags: types.SetNull(types.StringType), FilterBy: types.StringNull()}, errors: 1},
+		{name: "match without query", rule: OverrideRuleModel{Query: types.StringNull(), Match: types.StringValue("exact"), Tags: tags, FilterBy: types.StringNull()}, errors: 1},
+		{name: "empty rule", rule: OverrideRuleModel{Query: types.StringNull(), Match: types.StringNull(), Tags: types.SetNull(types.StringType), FilterBy: types.StringNull()}, errors: 1},
+		{name: "unknown query", rule: OverrideRuleModel{Query: types.StringUnknown(), Match: types.StringNull(), Tags: types.SetNull(types.StringType), FilterBy: types.StringNull()}},
+		{name: "effective range", rule: queryRule("shoes", "exact"), from: types.Int64Value(100), to: types.Int64Value(200)},
+		{name: "effective range reversed", rule: queryRule("shoes", "exact"), from: types.Int64Value(200), to: types.Int64Value(100), errors: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data := newOverrideModel(c.rule)
+			data.Id = types.StringNull()
+			data.EffectiveFromTs = c.from
+			data.EffectiveToTs = c.to
+
+			state := tfsdk.State{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			}
+
+			diags := state.Set(ctx, &data)
+
+			if diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+
+			resp := &resource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw},
+			}, resp)
+
+			if resp.Diagnostics.ErrorsCount() != c.errors {
+				t.Errorf("got %d errors, want %d: %v", resp.Diagnostics.ErrorsCount(), c.errors, resp.Diagnostics)
+			}
+		})
+	}
+}
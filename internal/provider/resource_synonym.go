@@ -14,21 +14,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-
-	"github.com/typesense/typesense-go/typesense"
-	"github.com/typesense/typesense-go/typesense/api"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &SynonymResource{}
 var _ resource.ResourceWithImportState = &SynonymResource{}
+var _ resource.ResourceWithUpgradeState = &SynonymResource{}
+var _ resource.ResourceWithModifyPlan = &SynonymResource{}
 
 func NewSynonymResource() resource.Resource {
 	return &SynonymResource{}
 }
 
 type SynonymResource struct {
-	client *typesense.Client
+	client *TypesenseClient
 }
 
 type SynonymResourceModel struct {
@@ -36,7 +35,10 @@ type SynonymResourceModel struct {
 	Name           types.String   `tfsdk:"name"`
 	CollectionName types.String   `tfsdk:"collection_name"`
 	Root           types.String   `tfsdk:"root"`
-	Synonyms       []types.String `tfsdk:"synonyms"`
+	Synonyms       SynonymsValue  `tfsdk:"synonyms"`
+	Locale         types.String   `tfsdk:"locale"`
+	SymbolsToIndex []types.String `tfsdk:"symbols_to_index"`
+	Type           types.String   `tfsdk:"type"`
 }
 
 func (r *SynonymResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -45,7 +47,9 @@ func (r *SynonymResource) Metadata(ctx context.Context, req resource.MetadataReq
 
 func (r *SynonymResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "The synonyms feature allows you to define search terms that should be considered equivalent. For eg: when you define a synonym for sneaker as shoe, searching for sneaker will now return all records with the word shoe in them, in addition to records with the word sneaker.",
+		Version: 1,
+
+		MarkdownDescription: "The synonyms feature allows you to define search terms that should be considered equivalent. For eg: when you define a synonym for sneaker as shoe, searching for sneaker will now return all records with the word shoe in them, in addition to records with the word sneaker. Typesense v30 and later keep synonyms in synonym sets, use typesense_synonym_set instead.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -76,8 +80,23 @@ func (r *SynonymResource) Schema(ctx context.Context, req resource.SchemaRequest
 			"synonyms": schema.ListAttribute{
 				Required:            true,
 				ElementType:         types.StringType,
+				CustomType:          NewSynonymsType(),
+				Validators:          []validator.List{listvalidator.SizeAtLeast(1), synonymsValidator{}},
+				MarkdownDescription: "Array of words that should be considered as synonyms. The order of the words does not matter, they must be unique and must not contain the root",
+			},
+			"locale": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Locale of the synonym, e.g. `ja` or `th`, so that the words are tokenized like the locale of the field they match",
+			},
+			"symbols_to_index": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Special characters which are indexed as part of the synonym words, e.g. `+` for `c++`",
 				Validators:          []validator.List{listvalidator.SizeAtLeast(1)},
-				MarkdownDescription: "Array of words that should be considered as synonyms.",
+			},
+			"type": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Either one-way when root is set, or multi-way",
 			},
 		},
 	}
@@ -89,12 +108,12 @@ func (r *SynonymResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*typesense.Client)
+	client, ok := req.ProviderData.(*TypesenseClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -103,6 +122,24 @@ func (r *SynonymResource) Configure(ctx context.Context, req resource.ConfigureR
 	r.client = client
 }
 
+func (r *SynonymResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is written to the server when the synonym is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var root types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("root"), &root)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("type"), synonymType(root))...)
+
+	if resp.Diagnostics.HasError() || r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.client.requireServerVersionBefore(ctx, 30, "The per-collection synonyms API", "Use typesense_synonym_set and link the set to the collection with synonym_sets instead.", path.Root("collection_name"))...)
+}
+
 func (r *SynonymResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data SynonymResourceModel
 
@@ -113,15 +150,12 @@ func (r *SynonymResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	schema := &api.SearchSynonymSchema{}
-
-	schema.Root = data.Root.ValueStringPointer()
-	schema.Synonyms = convertTerraformArrayToStringArray(data.Synonyms)
+	schema := expandSynonymSchema(data)
 
 	tflog.Info(ctx, "synonyms: "+fmt.Sprint(schema.Synonyms))
 	tflog.Info(ctx, "collection name: "+data.CollectionName.ValueString())
 
-	synonym, err := r.client.Collection(data.CollectionName.ValueString()).Synonyms().Upsert(ctx, data.Name.ValueString(), schema)
+	synonym, err := r.client.upsertSynonym(ctx, data.CollectionName.ValueString(), data.Name.ValueString(), schema)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create synonym, got error: %s", err))
@@ -130,7 +164,9 @@ func (r *SynonymResource) Create(ctx context.Context, req resource.CreateRequest
 
 	data.Id = types.StringValue(createId(data.CollectionName.ValueString(), *synonym.Id))
 	data.Root = types.StringPointerValue(synonym.Root)
-	data.Synonyms = convertStringArrayToTerraformArray(synonym.Synonyms)
+	data.Synonyms = NewSynonymsValue(synonym.Synonyms)
+	flattenSynonymSettings(&data, synonym)
+	data.Type = synonymType(data.Root)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -151,7 +187,7 @@ func (r *SynonymResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	synonym, err := r.client.Collection(collectionName).Synonym(id).Retrieve(ctx)
+	synonym, err := r.client.retrieveSynonym(ctx, collectionName, id)
 
 	if err != nil {
 		if strings.Contains(err.Error(), "Not Found") {
@@ -166,12 +202,15 @@ func (r *SynonymResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	// data.Id = types.StringPointerValue(synonym.Id)
 	data.Name = types.StringPointerValue(synonym.Id)
-	data.Synonyms = convertStringArrayToTerraformArray(synonym.Synonyms)
+	data.Synonyms = NewSynonymsValue(synonym.Synonyms)
 
 	if synonym.Root != nil && *synonym.Root != "" {
 		data.Root = types.StringPointerValue(synonym.Root)
 	}
 
+	flattenSynonymSettings(&data, synonym)
+	data.Type = synonymType(data.Root)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -185,10 +224,7 @@ func (r *SynonymResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	schema := &api.SearchSynonymSchema{}
-
-	schema.Root = data.Root.ValueStringPointer()
-	schema.Synonyms = convertTerraformArrayToStringArray(data.Synonyms)
+	schema := expandSynonymSchema(data)
 
 	tflog.Info(ctx, "synonyms: "+fmt.Sprint(schema.Synonyms))
 	tflog.Info(ctx, "collection name: "+data.CollectionName.ValueString())
@@ -199,7 +235,7 @@ func (r *SynonymResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	synonym, err := r.client.Collection(collectionName).Synonyms().Upsert(ctx, id, schema)
+	synonym, err := r.client.upsertSynonym(ctx, collectionName, id, schema)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create synonym, got error: %s", err))
@@ -209,7 +245,9 @@ func (r *SynonymResource) Update(ctx context.Context, req resource.UpdateRequest
 	// data.Id = types.StringPointerValue(synonym.Id)
 	data.Name = types.StringPointerValue(synonym.Id)
 	data.Root = types.StringPointerValue(synonym.Root)
-	data.Synonyms = convertStringArrayToTerraformArray(synonym.Synonyms)
+	data.Synonyms = NewSynonymsValue(synonym.Synonyms)
+	flattenSynonymSettings(&data, synonym)
+	data.Type = synonymType(data.Root)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -248,5 +286,60 @@ func (r *SynonymResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *SynonymResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	collectionName, name, err := splitCollectionRelatedId(req.ID)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unable to split resource ID: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), createId(collectionName, name))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("collection_name"), collectionName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+func (r *SynonymResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 ids joined the collection and the synonym name with a dot
+		0: {StateUpgrader: upgradeSynonymState},
+	}
+}
+
+// upgradeSynonymState upgrades version 0 state and sets the type, which
+// version 0 did not have.
+func upgradeSynonymState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	upgradeCollectionRelatedIdState(ctx, req, resp, func(state map[string]interface{}) {
+		root, _ := state["root"].(string)
+		state["type"] = synonymType(types.StringValue(root)).ValueString()
+	})
+}
+
+func expandSynonymSchema(data SynonymResourceModel) *synonymSchema {
+	schema := &synonymSchema{}
+
+	schema.Root = data.Root.ValueStringPointer()
+	schema.Synonyms = data.Synonyms.Strings()
+	schema.Locale = data.Locale.ValueStringPointer()
+
+	if len(data.SymbolsToIndex) > 0 {
+		schema.SymbolsToIndex = convertTerraformArrayToStringArray(data.SymbolsToIndex)
+	}
+
+	return schema
+}
+
+// flattenSynonymSettings sets locale and symbols_to_index from the server,
+// empty values which the server returns for unset settings are kept null.
+func flattenSynonymSettings(data *SynonymResourceModel, synonym *synonymResponse) {
+	data.Locale = types.StringNull()
+
+	if synonym.Locale != nil && *synonym.Locale != "" {
+		data.Locale = types.StringPointerValue(synonym.Locale)
+	}
+
+	data.SymbolsToIndex = nil
+
+	if len(synonym.SymbolsToIndex) > 0 {
+		data.SymbolsToIndex = convertStringArrayToTerraformArray(synonym.SymbolsToIndex)
+	}
 }
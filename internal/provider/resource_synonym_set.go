@@ -0,0 +1,361 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SynonymSetResource{}
+var _ resource.ResourceWithImportState = &SynonymSetResource{}
+var _ resource.ResourceWithModifyPlan = &SynonymSetResource{}
+var _ resource.ResourceWithValidateConfig = &SynonymSetResource{}
+
+func NewSynonymSetResource() resource.Resource {
+	return &SynonymSetResource{}
+}
+
+type SynonymSetResource struct {
+	client *TypesenseClient
+}
+
+type SynonymSetResourceModel struct {
+	Id    types.String          `tfsdk:"id"`
+	Name  types.String          `tfsdk:"name"`
+	Items []SynonymSetItemModel `tfsdk:"items"`
+}
+
+type SynonymSetItemModel struct {
+	Id             types.String   `tfsdk:"id"`
+	Root           types.String   `tfsdk:"root"`
+	Synonyms       SynonymsValue  `tfsdk:"synonyms"`
+	Locale         types.String   `tfsdk:"locale"`
+	SymbolsToIndex []types.String `tfsdk:"symbols_to_index"`
+	Type           types.String   `tfsdk:"type"`
+}
+
+func (r *SynonymSetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_synonym_set"
+}
+
+func (r *SynonymSetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Standalone set of synonyms, which is linked to collections with the synonym_sets attribute of typesense_collection. Synonym sets replace the per-collection synonyms of typesense_synonym and require Typesense v30 or later",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Id identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the synonym set",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"items": schema.ListNestedBlock{
+				MarkdownDescription: "Synonyms of the set",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Id of the synonym within the set",
+						},
+						"root": schema.StringAttribute{
+							Optional:    true,
+							Description: "For 1-way synonyms, indicates the root word that words in the synonyms parameter map to",
+						},
+						"synonyms": schema.ListAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							CustomType:  NewSynonymsType(),
+							Validators:  []validator.List{listvalidator.SizeAtLeast(1), synonymsValidator{}},
+							Description: "Array of words that should be considered as synonyms. The order of the words does not matter, they must be unique and must not contain the root",
+						},
+						"locale": schema.StringAttribute{
+							Optional:    true,
+							Description: "Locale of the synonym, e.g. ja or th",
+						},
+						"symbols_to_index": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Special characters which are indexed as part of the synonym words, e.g. + for c++",
+							Validators:  []validator.List{listvalidator.SizeAtLeast(1)},
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Either one-way when root is set, or multi-way",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *SynonymSetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*TypesenseClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *SynonymSetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var items types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("items"), &items)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The server keeps one synonym per id, a duplicate id would replace an
+	// earlier item of the set
+	seen := make(map[string]bool)
+
+	for i := range items.Elements() {
+		var id types.String
+
+		itemPath := path.Root("items").AtListIndex(i).AtName("id")
+
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, itemPath, &id)...)
+
+		if id.IsNull() || id.IsUnknown() {
+			continue
+		}
+
+		if seen[id.ValueString()] {
+			resp.Diagnostics.AddAttributeError(itemPath, "Duplicate Synonym Id", fmt.Sprintf("The id %q is used by more than one item of the set.", id.ValueString()))
+			continue
+		}
+
+		seen[id.ValueString()] = true
+	}
+}
+
+func (r *SynonymSetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is written to the server when the set is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var items types.List
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("items"), &items)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i := range items.Elements() {
+		var root types.String
+
+		itemPath := path.Root("items").AtListIndex(i)
+
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, itemPath.AtName("root"), &root)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, itemPath.AtName("type"), synonymType(root))...)
+	}
+
+	// Only check the server when the set is created
+	if resp.Diagnostics.HasError() || !req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.client.requireServerVersion(ctx, 30, "Synonym sets", path.Root("name"))...)
+}
+
+func (r *SynonymSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SynonymSetResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "###Create synonym set: "+data.Name.ValueString())
+
+	_, err := r.client.upsertSynonymSet(ctx, data.Name.ValueString(), expandSynonymSet(data))
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create synonym set, got error: %s", err))
+		return
+	}
+
+	// The planned items are kept, the server may return them in a different
+	// order or spelling, only type is computed
+	data.Id = types.StringValue(data.Name.ValueString())
+	setSynonymSetItemTypes(data.Items)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SynonymSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SynonymSetResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	set, err := r.client.retrieveSynonymSet(ctx, data.Id.ValueString())
+
+	if err != nil {
+		if strings.Contains(err.Error(), "Not Found") {
+			resp.State.RemoveResource(ctx)
+			resp.Diagnostics.AddWarning("Resource Not Found", fmt.Sprintf("Unable to find synonym set %s, removing from state", data.Id.ValueString()))
+		} else {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to retrieve synonym set, got error: %s", err))
+		}
+
+		return
+	}
+
+	data.Name = types.StringValue(data.Id.ValueString())
+	data.Items = flattenSynonymSetItems(set.Items)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SynonymSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SynonymSetResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "###Update synonym set: "+data.Id.ValueString())
+
+	// The items of a set are always replaced as a whole
+	_, err := r.client.upsertSynonymSet(ctx, data.Id.ValueString(), expandSynonymSet(data))
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update synonym set, got error: %s", err))
+		return
+	}
+
+	setSynonymSetItemTypes(data.Items)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SynonymSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SynonymSetResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Warn(ctx, "###Delete synonym set with id="+data.Id.ValueString())
+
+	err := r.client.deleteSynonymSet(ctx, data.Id.ValueString())
+
+	if err != nil {
+		if strings.Contains(err.Error(), "Not Found") {
+			resp.State.RemoveResource(ctx)
+		} else {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete synonym set, got error: %s", err))
+		}
+
+		return
+	}
+}
+
+func (r *SynonymSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func expandSynonymSet(data SynonymSetResourceModel) *synonymSet {
+	set := &synonymSet{Items: []synonymSetItem{}}
+
+	for _, item := range data.Items {
+		set.Items = append(set.Items, synonymSetItem{
+			Id:             item.Id.ValueString(),
+			Root:           item.Root.ValueString(),
+			Synonyms:       item.Synonyms.Strings(),
+			Locale:         item.Locale.ValueString(),
+			SymbolsToIndex: convertTerraformArrayToStringArray(item.SymbolsToIndex),
+		})
+	}
+
+	return set
+}
+
+// setSynonymSetItemTypes sets the computed type of every item from its root
+func setSynonymSetItemTypes(items []SynonymSetItemModel) {
+	for i := range items {
+		items[i].Type = synonymType(items[i].Root)
+	}
+}
+
+// flattenSynonymSetItems converts the items returned by the server, empty
+// values which the server returns for unset settings are kept null.
+func flattenSynonymSetItems(items []synonymSetItem) []SynonymSetItemModel {
+	result := []SynonymSetItemModel{}
+
+	for _, item := range items {
+		model := SynonymSetItemModel{
+			Id:       types.StringValue(item.Id),
+			Root:     types.StringNull(),
+			Synonyms: NewSynonymsValue(item.Synonyms),
+			Locale:   types.StringNull(),
+		}
+
+		if item.Root != "" {
+			model.Root = types.StringValue(item.Root)
+		}
+
+		model.Type = synonymType(model.Root)
+
+		if item.Locale != "" {
+			model.Locale = types.StringValue(item.Locale)
+		}
+
+		if len(item.SymbolsToIndex) > 0 {
+			model.SymbolsToIndex = convertStringArrayToTerraformArray(item.SymbolsToIndex)
+		}
+
+		result = append(result, model)
+	}
+
+	return result
+}
@@ -0,0 +1,671 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SynonymsFileResource{}
+var _ resource.ResourceWithConfigValidators = &SynonymsFileResource{}
+var _ resource.ResourceWithModifyPlan = &SynonymsFileResource{}
+
+func NewSynonymsFileResource() resource.Resource {
+	return &SynonymsFileResource{}
+}
+
+type SynonymsFileResource struct {
+	client *TypesenseClient
+}
+
+type SynonymsFileResourceModel struct {
+	Id             types.String `tfsdk:"id"`
+	CollectionName types.String `tfsdk:"collection_name"`
+	SourceFile     types.String `tfsdk:"source_file"`
+	Content        types.String `tfsdk:"content"`
+	IdPrefix       types.String `tfsdk:"id_prefix"`
+	Locale         types.String `tfsdk:"locale"`
+	SymbolsToIndex types.List   `tfsdk:"symbols_to_index"`
+	SynonymHashes  types.Map    `tfsdk:"synonym_hashes"`
+}
+
+// parsedSynonyms holds the synonyms of a synonyms file by their derived id,
+// ids keeps the order in which the synonyms were declared.
+type parsedSynonyms struct {
+	ids      []string
+	synonyms map[string]*synonymSchema
+}
+
+func (r *SynonymsFileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_synonyms_file"
+}
+
+func (r *SynonymsFileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the synonyms of a synonyms file in the Solr format used by Solr and Elasticsearch. Lines like `a, b, c` become multi-way synonyms and lines like `a, b => c` become one-way synonyms with a and b as root. Synonym ids are derived from the terms, so only changed lines are written and removed lines are deleted",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Id identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "Collection name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path to the synonyms file",
+			},
+			"content": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Content of the synonyms file",
+			},
+			"id_prefix": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Prefix of the derived synonym ids, must be unique per collection when several files are imported into the same collection",
+				Default:             stringdefault.StaticString("solr"),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"locale": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Locale of every synonym of the file, e.g. `ja` or `th`",
+			},
+			"symbols_to_index": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Special characters which are indexed as part of the synonym words of every synonym of the file, e.g. `+` for `c++`",
+			},
+			"synonym_hashes": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "SHA-256 hashes of the applied synonyms by synonym id, used to write only changed synonyms",
+			},
+		},
+	}
+}
+
+func (r *SynonymsFileResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("source_file"),
+			path.MatchRoot("content"),
+		),
+	}
+}
+
+func (r *SynonymsFileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*TypesenseClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *TypesenseClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *SynonymsFileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan SynonymsFileResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.SourceFile.IsUnknown() || plan.Content.IsUnknown() || plan.IdPrefix.IsUnknown() || plan.Locale.IsUnknown() || plan.SymbolsToIndex.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("synonym_hashes"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	parsed, diags := loadSynonymsFile(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hashes, diags := hashSynonyms(parsed.synonyms)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("synonym_hashes"), hashes)...)
+
+	if resp.Diagnostics.HasError() || r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.client.requireServerVersionBefore(ctx, 30, "The per-collection synonyms API", "Use typesense_synonym_set and link the set to the collection with synonym_sets instead.", path.Root("collection_name"))...)
+}
+
+func (r *SynonymsFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SynonymsFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(createId(data.CollectionName.ValueString(), data.IdPrefix.ValueString()))
+
+	r.applySynonyms(ctx, &data, map[string]string{}, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SynonymsFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SynonymsFileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied := make(map[string]string)
+	resp.Diagnostics.Append(data.SynonymHashes.ElementsAs(ctx, &applied, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	synonyms, err := r.client.listSynonyms(ctx, data.CollectionName.ValueString())
+
+	if err != nil {
+		if strings.Contains(err.Error(), "Not Found") {
+			resp.State.RemoveResource(ctx)
+			resp.Diagnostics.AddWarning("Resource Not Found", fmt.Sprintf("Unable to find collection %s, removing synonyms from state", data.CollectionName.ValueString()))
+		} else {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to retrieve synonyms, got error: %s", err))
+		}
+
+		return
+	}
+
+	current := make(map[string]string)
+
+	for i := range synonyms {
+		if synonyms[i].Id == nil {
+			continue
+		}
+
+		if _, ok := applied[*synonyms[i].Id]; !ok {
+			continue
+		}
+
+		hash, err := synonymHash(synonymSchemaFromResponse(&synonyms[i]))
+		if err != nil {
+			resp.Diagnostics.AddError("JSON format error", fmt.Sprintf("Unable to hash synonym %s, got error: %s", *synonyms[i].Id, err))
+			return
+		}
+
+		current[*synonyms[i].Id] = hash
+	}
+
+	var diags diag.Diagnostics
+
+	data.SynonymHashes, diags = types.MapValueFrom(ctx, types.StringType, current)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SynonymsFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan SynonymsFileResourceModel
+	var state SynonymsFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied := make(map[string]string)
+	resp.Diagnostics.Append(state.SynonymHashes.ElementsAs(ctx, &applied, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(state.Id.ValueString())
+
+	r.applySynonyms(ctx, &plan, applied, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *SynonymsFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SynonymsFileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied := make(map[string]string)
+	resp.Diagnostics.Append(data.SynonymHashes.ElementsAs(ctx, &applied, false)...)
+
+	tflog.Warn(ctx, fmt.Sprintf("###Delete %d synonyms of collection %s", len(applied), data.CollectionName.ValueString()))
+
+	for id := range applied {
+		_, err := r.client.Collection(data.CollectionName.ValueString()).Synonym(id).Delete(ctx)
+
+		if err != nil && !strings.Contains(err.Error(), "Not Found") {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete synonym %s, got error: %s", id, err))
+			return
+		}
+	}
+}
+
+// applySynonyms writes the synonyms of the file whose hash differs from the
+// applied one and deletes the applied synonyms which are no longer part of
+// the file. The hashes of the synonyms which were written successfully are
+// stored, so that a failed apply only retries the remaining synonyms.
+func (r *SynonymsFileResource) applySynonyms(ctx context.Context, data *SynonymsFileResourceModel, applied map[string]string, diags *diag.Diagnostics) {
+	defer func() {
+		var mapDiags diag.Diagnostics
+
+		data.SynonymHashes, mapDiags = types.MapValueFrom(ctx, types.StringType, applied)
+		diags.Append(mapDiags...)
+	}()
+
+	parsed, loadDiags := loadSynonymsFile(ctx, *data)
+	diags.Append(loadDiags...)
+
+	if diags.HasError() {
+		return
+	}
+
+	hashes, hashDiags := hashSynonyms(parsed.synonyms)
+	diags.Append(hashDiags...)
+
+	if diags.HasError() {
+		return
+	}
+
+	collectionName := data.CollectionName.ValueString()
+
+	written := 0
+
+	for _, id := range parsed.ids {
+		if applied[id] == hashes[id] {
+			continue
+		}
+
+		_, err := r.client.upsertSynonym(ctx, collectionName, id, parsed.synonyms[id])
+
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to upsert synonym %s, got error: %s", id, err))
+			return
+		}
+
+		applied[id] = hashes[id]
+		written++
+	}
+
+	removed := []string{}
+
+	for id := range applied {
+		if _, ok := parsed.synonyms[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+
+	sort.Strings(removed)
+
+	tflog.Info(ctx, fmt.Sprintf("###Synonyms written: %d, deleted: %d", written, len(removed)))
+
+	for _, id := range removed {
+		_, err := r.client.Collection(collectionName).Synonym(id).Delete(ctx)
+
+		if err != nil && !strings.Contains(err.Error(), "Not Found") {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to delete synonym %s, got error: %s", id, err))
+			return
+		}
+
+		delete(applied, id)
+	}
+}
+
+// loadSynonymsFile reads the synonyms file, either from source_file or from
+// content, and applies the settings shared by every synonym of the file.
+func loadSynonymsFile(ctx context.Context, data SynonymsFileResourceModel) (*parsedSynonyms, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	attributePath := path.Root("content")
+	content := data.Content.ValueString()
+
+	if !data.SourceFile.IsNull() {
+		attributePath = path.Root("source_file")
+
+		fileContent, err := os.ReadFile(data.SourceFile.ValueString())
+
+		if err != nil {
+			diags.AddAttributeError(attributePath, "Invalid Synonyms File", fmt.Sprintf("Unable to read synonyms file, got error: %s", err))
+			return nil, diags
+		}
+
+		content = string(fileContent)
+	}
+
+	parsed, diags := parseSolrSynonyms(content, data.IdPrefix.ValueString(), attributePath)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var symbolsToIndex []string
+
+	diags.Append(data.SymbolsToIndex.ElementsAs(ctx, &symbolsToIndex, false)...)
+
+	for _, synonym := range parsed.synonyms {
+		synonym.Locale = data.Locale.ValueStringPointer()
+
+		if len(symbolsToIndex) > 0 {
+			synonym.SymbolsToIndex = symbolsToIndex
+		}
+	}
+
+	return parsed, diags
+}
+
+// parseSolrSynonyms parses synonyms in the Solr format. Every line holds
+// either comma separated equivalent terms, which become a multi-way synonym,
+// or an explicit mapping `a, b => c, d`, which becomes a one-way synonym per
+// term on the left side. Mappings of the same term are merged, like Solr
+// does. Lines starting with # are comments and commas can be escaped with a
+// backslash. Errors are reported with the line number of the file.
+func parseSolrSynonyms(content string, idPrefix string, attributePath path.Path) (*parsedSynonyms, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	parsed := &parsedSynonyms{synonyms: make(map[string]*synonymSchema)}
+
+	for i, line := range strings.Split(content, "\n") {
+		lineNumber := i + 1
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sides := splitEscaped(line, "=>", false)
+
+		if len(sides) > 2 {
+			diags.AddAttributeError(attributePath, "Invalid Synonyms File", fmt.Sprintf("Line %d: a mapping can contain => only once.", lineNumber))
+			continue
+		}
+
+		terms := make([][]string, len(sides))
+		valid := true
+
+		for j, side := range sides {
+			terms[j] = solrTerms(side)
+
+			if terms[j] == nil {
+				diags.AddAttributeError(attributePath, "Invalid Synonyms File", fmt.Sprintf("Line %d: terms must not be empty.", lineNumber))
+				valid = false
+				break
+			}
+		}
+
+		if !valid {
+			continue
+		}
+
+		if len(sides) == 2 {
+			for _, root := range terms[0] {
+				id := synonymId(idPrefix, "=>", root)
+
+				synonym, ok := parsed.synonyms[id]
+
+				if !ok {
+					rootTerm := root
+					synonym = &synonymSchema{}
+					synonym.Root = &rootTerm
+					synonym.Synonyms = []string{}
+				}
+
+				for _, term := range terms[1] {
+					if term != root && !containsString(synonym.Synonyms, term) {
+						synonym.Synonyms = append(synonym.Synonyms, term)
+					}
+				}
+
+				// A term which maps only to itself does not need a synonym
+				if !ok && len(synonym.Synonyms) > 0 {
+					parsed.ids = append(parsed.ids, id)
+					parsed.synonyms[id] = synonym
+				}
+			}
+
+			continue
+		}
+
+		if len(terms[0]) < 2 {
+			diags.AddAttributeError(attributePath, "Invalid Synonyms File", fmt.Sprintf("Line %d: at least two comma separated terms or a => mapping are required.", lineNumber))
+			continue
+		}
+
+		sorted := append([]string{}, terms[0]...)
+		sort.Strings(sorted)
+
+		id := synonymId(idPrefix, ",", sorted...)
+
+		// The same terms may be declared more than once
+		if _, ok := parsed.synonyms[id]; ok {
+			continue
+		}
+
+		parsed.ids = append(parsed.ids, id)
+		parsed.synonyms[id] = &synonymSchema{}
+		parsed.synonyms[id].Synonyms = terms[0]
+	}
+
+	return parsed, diags
+}
+
+// solrTerms splits one side of a Solr synonyms line into unique terms, with
+// the whitespace within terms collapsed. nil is returned when a term is
+// empty.
+func solrTerms(side string) []string {
+	terms := []string{}
+
+	for _, term := range splitEscaped(side, ",", true) {
+		term = strings.Join(strings.Fields(term), " ")
+
+		if term == "" {
+			return nil
+		}
+
+		if !containsString(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// splitEscaped splits s at every occurrence of sep which is not escaped with
+// a backslash. Escape sequences are resolved when unescape is true and kept
+// otherwise.
+func splitEscaped(s string, sep string, unescape bool) []string {
+	parts := []string{}
+
+	var current strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			if !unescape {
+				current.WriteByte('\\')
+			}
+
+			current.WriteByte(s[i+1])
+			i++
+
+			continue
+		}
+
+		if strings.HasPrefix(s[i:], sep) {
+			parts = append(parts, current.String())
+			current.Reset()
+			i += len(sep) - 1
+
+			continue
+		}
+
+		current.WriteByte(s[i])
+	}
+
+	return append(parts, current.String())
+}
+
+// synonymId derives a stable synonym id from the kind of the synonym and its
+// terms, so that the same line always maps to the same synonym.
+func synonymId(idPrefix string, kind string, terms ...string) string {
+	sum := sha256.Sum256([]byte(kind + "\x00" + strings.Join(terms, "\x00")))
+
+	return idPrefix + "-" + hex.EncodeToString(sum[:8])
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// synonymSchemaFromResponse converts a synonym returned by the server into
+// the schema it was written with, empty values which the server returns for
+// unset settings are dropped.
+func synonymSchemaFromResponse(synonym *synonymResponse) *synonymSchema {
+	schema := &synonymSchema{}
+
+	schema.Synonyms = synonym.Synonyms
+
+	if synonym.Root != nil && *synonym.Root != "" {
+		schema.Root = synonym.Root
+	}
+
+	if synonym.Locale != nil && *synonym.Locale != "" {
+		schema.Locale = synonym.Locale
+	}
+
+	if len(synonym.SymbolsToIndex) > 0 {
+		schema.SymbolsToIndex = synonym.SymbolsToIndex
+	}
+
+	return schema
+}
+
+// synonymHash hashes the synonym in the form Typesense matches it, so a
+// synonym returned in another case or order has the same hash.
+func synonymHash(synonym *synonymSchema) (string, error) {
+	jsonBytes, err := json.Marshal(normalizedSynonymSchema(synonym))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(jsonBytes)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalizedSynonymSchema returns a copy of the synonym with normalized and
+// sorted words and sorted symbols.
+func normalizedSynonymSchema(synonym *synonymSchema) *synonymSchema {
+	normalized := *synonym
+
+	if synonym.Root != nil {
+		root := normalizeSynonym(*synonym.Root)
+		normalized.Root = &root
+	}
+
+	normalized.Synonyms = make([]string, len(synonym.Synonyms))
+
+	for i, word := range synonym.Synonyms {
+		normalized.Synonyms[i] = normalizeSynonym(word)
+	}
+
+	sort.Strings(normalized.Synonyms)
+
+	if len(synonym.SymbolsToIndex) > 0 {
+		normalized.SymbolsToIndex = append([]string{}, synonym.SymbolsToIndex...)
+		sort.Strings(normalized.SymbolsToIndex)
+	}
+
+	return &normalized
+}
+
+func hashSynonyms(synonyms map[string]*synonymSchema) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	hashes := make(map[string]string, len(synonyms))
+
+	for id, synonym := range synonyms {
+		hash, err := synonymHash(synonym)
+
+		if err != nil {
+			diags.AddError("JSON format error", fmt.Sprintf("Unable to hash synonym %s, got error: %s", id, err))
+			continue
+		}
+
+		hashes[id] = hash
+	}
+
+	return hashes, diags
+}
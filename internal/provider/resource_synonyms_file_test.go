@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestParseSolrSynonyms(t *testing.T) {
+	content := strings.Join([]string{
+		"# comment",
+		"",
+		"ipod, i-pod,  i pod",
+		"i-pod, ipod, i pod",
+		"sea biscuit, sea biscit => seabiscuit",
+		"sea biscuit => sea biscuit, biscuit",
+		"c\\,d, cd",
+		"foo => foo",
+	}, "\n")
+
+	parsed, diags := parseSolrSynonyms(content, "solr", path.Root("content"))
+
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	var got [][]string
+
+	for _, id := range parsed.ids {
+		synonym := parsed.synonyms[id]
+
+		root := ""
+		if synonym.Root != nil {
+			root = *synonym.Root
+		}
+
+		got = append(got, append([]string{root}, synonym.Synonyms...))
+	}
+
+	want := [][]string{
+		{"", "ipod", "i-pod", "i pod"},
+		{"sea biscuit", "seabiscuit", "biscuit"},
+		{"sea biscit", "seabiscuit"},
+		{"", "c,d", "cd"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsed = %q, want %q", got, want)
+	}
+
+	again, _ := parseSolrSynonyms(content, "solr", path.Root("content"))
+
+	if !reflect.DeepEqual(again.ids, parsed.ids) {
+		t.Errorf("ids are not deterministic: %v != %v", again.ids, parsed.ids)
+	}
+}
+
+func TestParseSolrSynonymsReportsLineNumbers(t *testing.T) {
+	content := "a, b\nsingle\na => b => c\na, , b"
+
+	_, diags := parseSolrSynonyms(content, "solr", path.Root("content"))
+
+	if diags.ErrorsCount() != 3 {
+		t.Fatalf("got %d errors, want 3: %v", diags.ErrorsCount(), diags)
+	}
+
+	for i, line := range []string{"Line 2:", "Line 3:", "Line 4:"} {
+		if detail := diags.Errors()[i].Detail(); !strings.HasPrefix(detail, line) {
+			t.Errorf("error %d = %q, want prefix %q", i, detail, line)
+		}
+	}
+}
+
+func TestSynonymHashIgnoresCaseAndOrder(t *testing.T) {
+	root := "Sea  Biscuit"
+	declared := &synonymSchema{}
+	declared.Root = &root
+	declared.Synonyms = []string{"Seabiscuit", "biscuit"}
+
+	id := "sea-biscuit"
+	serverRoot := "sea biscuit"
+	response := &synonymResponse{}
+	response.Id = &id
+	response.Root = &serverRoot
+	response.Synonyms = []string{"biscuit", "seabiscuit"}
+
+	want, err := synonymHash(declared)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := synonymHash(synonymSchemaFromResponse(response))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got != want {
+		t.Errorf("hash of the server synonym = %s, want %s", got, want)
+	}
+
+	if declared.Synonyms[0] != "Seabiscuit" || *declared.Root != "Sea  Biscuit" {
+		t.Errorf("the declared synonym was modified: %q %q", *declared.Root, declared.Synonyms)
+	}
+}
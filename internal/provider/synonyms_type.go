@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ basetypes.ListTypable = SynonymsType{}
+var _ basetypes.ListValuableWithSemanticEquals = SynonymsValue{}
+var _ validator.List = synonymsValidator{}
+
+// SynonymsType is a list of synonym words whose order does not matter.
+// Lists which contain the same words, ignoring order, case and surrounding
+// whitespace, are semantically equal, so that the order returned by the
+// server does not show up as a difference.
+type SynonymsType struct {
+	basetypes.ListType
+}
+
+func NewSynonymsType() SynonymsType {
+	return SynonymsType{ListType: basetypes.ListType{ElemType: types.StringType}}
+}
+
+func (t SynonymsType) Equal(o attr.Type) bool {
+	other, ok := o.(SynonymsType)
+
+	if !ok {
+		return false
+	}
+
+	return t.ListType.Equal(other.ListType)
+}
+
+func (t SynonymsType) String() string {
+	return "SynonymsType"
+}
+
+func (t SynonymsType) ValueFromList(ctx context.Context, in basetypes.ListValue) (basetypes.ListValuable, diag.Diagnostics) {
+	return SynonymsValue{ListValue: in}, nil
+}
+
+func (t SynonymsType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.ListType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	listValue, ok := attrValue.(basetypes.ListValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return SynonymsValue{ListValue: listValue}, nil
+}
+
+func (t SynonymsType) ValueType(ctx context.Context) attr.Value {
+	return SynonymsValue{}
+}
+
+// SynonymsValue is the value of a SynonymsType.
+type SynonymsValue struct {
+	basetypes.ListValue
+}
+
+// NewSynonymsValue returns a known SynonymsValue with the given words.
+func NewSynonymsValue(synonyms []string) SynonymsValue {
+	elements := make([]attr.Value, len(synonyms))
+
+	for i, synonym := range synonyms {
+		elements[i] = types.StringValue(synonym)
+	}
+
+	return SynonymsValue{ListValue: types.ListValueMust(types.StringType, elements)}
+}
+
+func (v SynonymsValue) Equal(o attr.Value) bool {
+	other, ok := o.(SynonymsValue)
+
+	if !ok {
+		return false
+	}
+
+	return v.ListValue.Equal(other.ListValue)
+}
+
+func (v SynonymsValue) Type(ctx context.Context) attr.Type {
+	return NewSynonymsType()
+}
+
+// Strings returns the words of the list, unknown and null words are skipped.
+func (v SynonymsValue) Strings() []string {
+	result := []string{}
+
+	for _, element := range v.Elements() {
+		if s, ok := element.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			result = append(result, s.ValueString())
+		}
+	}
+
+	return result
+}
+
+func (v SynonymsValue) ListSemanticEquals(ctx context.Context, newValuable basetypes.ListValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(SynonymsValue)
+
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+
+		return false, diags
+	}
+
+	return synonymWordsEqual(v.Strings(), newValue.Strings()), diags
+}
+
+// normalizeSynonym returns the form in which Typesense matches a synonym
+// word, lower case and with the whitespace collapsed.
+func normalizeSynonym(synonym string) string {
+	return strings.ToLower(strings.Join(strings.Fields(synonym), " "))
+}
+
+// synonymWordsEqual reports whether both lists contain the same words,
+// ignoring order and normalization.
+func synonymWordsEqual(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[string]int, len(a))
+
+	for _, word := range a {
+		counts[normalizeSynonym(word)]++
+	}
+
+	for _, word := range b {
+		key := normalizeSynonym(word)
+
+		if counts[key] == 0 {
+			return false
+		}
+
+		counts[key]--
+	}
+
+	return true
+}
+
+// synonymType describes a synonym as one-way when it has a root and as
+// multi-way otherwise.
+func synonymType(root types.String) types.String {
+	if root.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	if root.IsNull() || root.ValueString() == "" {
+		return types.StringValue("multi-way")
+	}
+
+	return types.StringValue("one-way")
+}
+
+// synonymsValidator rejects empty and duplicate words and a root, read from
+// the sibling root attribute, which is also listed as synonym.
+type synonymsValidator struct{}
+
+func (v synonymsValidator) Description(ctx context.Context) string {
+	return "synonyms must not be empty, must be unique and must not contain the root"
+}
+
+func (v synonymsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v synonymsValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	seen := make(map[string]bool)
+
+	for i, element := range req.ConfigValue.Elements() {
+		word, ok := element.(types.String)
+
+		if !ok || word.IsUnknown() || word.IsNull() {
+			continue
+		}
+
+		key := normalizeSynonym(word.ValueString())
+
+		if key == "" {
+			resp.Diagnostics.AddAttributeError(req.Path.AtListIndex(i), "Invalid Synonym", "Synonyms must not be empty.")
+			continue
+		}
+
+		if seen[key] {
+			resp.Diagnostics.AddAttributeError(req.Path.AtListIndex(i), "Invalid Synonym", fmt.Sprintf("%q is listed more than once.", word.ValueString()))
+			continue
+		}
+
+		seen[key] = true
+	}
+
+	var root types.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, req.Path.ParentPath().AtName("root"), &root)...)
+
+	if root.IsNull() || root.IsUnknown() {
+		return
+	}
+
+	if seen[normalizeSynonym(root.ValueString())] {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Synonym", fmt.Sprintf("The root %q must not be listed in synonyms, the root is always matched.", root.ValueString()))
+	}
+}
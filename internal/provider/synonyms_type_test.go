@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSynonymsValueSemanticEquals(t *testing.T) {
+	cases := []struct {
+		a, b  []string
+		equal bool
+	}{
+		{[]string{"iphone", "android"}, []string{"android", "iphone"}, true},
+		{[]string{"Smart  Phone", "iphone"}, []string{"iphone", "smart phone"}, true},
+		{[]string{"iphone", "android"}, []string{"iphone", "pixel"}, false},
+		{[]string{"iphone", "iphone"}, []string{"iphone", "android"}, false},
+		{[]string{"iphone"}, []string{"iphone", "android"}, false},
+	}
+
+	for _, c := range cases {
+		equal, diags := NewSynonymsValue(c.a).ListSemanticEquals(context.Background(), NewSynonymsValue(c.b))
+
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		if equal != c.equal {
+			t.Errorf("%q == %q = %t, want %t", c.a, c.b, equal, c.equal)
+		}
+	}
+}
@@ -1,12 +1,24 @@
 package provider
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
 // convert []types.String to []string
@@ -27,16 +39,33 @@ func convertStringArrayToTerraformArray(array []string) []types.String {
 	return arrayString
 }
 
-// parse string json to map[string]interface{}
+// parse string json to map[string]interface{}, numbers are kept as json.Number
 func parseJsonStringToMap(jsonString string) (map[string]interface{}, error) {
 	var result map[string]interface{}
-	err := json.Unmarshal([]byte(jsonString), &result)
+	err := unmarshalJson([]byte(jsonString), &result)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
+// unmarshal json like json.Unmarshal, but decode numbers as json.Number so that
+// integers above 2^53 are not rounded to float64
+func unmarshalJson(data []byte, result interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(result); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+
+	return nil
+}
+
 // convert map[string]interface{} to string json
 func parseMapToJsonString(data map[string]interface{}) (jsontypes.Normalized, error) {
 	jsonBytes, err := json.Marshal(data)
@@ -46,15 +75,361 @@ func parseMapToJsonString(data map[string]interface{}) (jsontypes.Normalized, er
 	return jsontypes.NewNormalizedValue(string(jsonBytes)), nil
 }
 
+// split an id created by createId into the collection name and the name of
+// the resource. Ids in the legacy format <collection>.<resource> are accepted
+// as long as neither part contains a dot.
 func splitCollectionRelatedId(input string) (string, string, error) {
+	if collection, name, ok := strings.Cut(input, "/"); ok {
+		collection, err := url.PathUnescape(collection)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid collection name in %q: %w", input, err)
+		}
+
+		name, err := url.PathUnescape(name)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid resource name in %q: %w", input, err)
+		}
+
+		if collection == "" || name == "" {
+			return "", "", fmt.Errorf("invalid format, format should be <collection>/<resource>")
+		}
+
+		return collection, name, nil
+	}
+
 	eles := strings.Split(input, ".")
 	if len(eles) != 2 {
-		return "", "", fmt.Errorf("invalid format, format should be <collection>.<resource>")
+		return "", "", fmt.Errorf("invalid format, format should be <collection>/<resource> with both parts URL-escaped")
 	}
 
 	return eles[0], eles[1], nil
 }
 
-func createId(collection string, resource string) string {
-	return fmt.Sprintf("%s.%s", collection, resource)
+// create an id from a collection name and the name of a resource of the
+// collection, both parts are URL-escaped so that they may contain dots and
+// slashes
+func createId(collection string, name string) string {
+	return fmt.Sprintf("%s/%s", url.PathEscape(collection), url.PathEscape(name))
+}
+
+// upgrade the state of a resource with an id in the legacy format
+// <collection>.<resource> to the format of createId. The collection_name
+// attribute resolves ids whose resource name contains dots. setDefaults fills
+// in attributes which were added since version 0, so that the first plan
+// after the upgrade does not update every resource.
+func upgradeCollectionRelatedIdState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse, setDefaults func(state map[string]interface{})) {
+	var state map[string]interface{}
+
+	if err := unmarshalJson(req.RawState.JSON, &state); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to parse prior state, got error: %s", err))
+		return
+	}
+
+	id, _ := state["id"].(string)
+	collectionName, _ := state["collection_name"].(string)
+
+	if id != "" {
+		var name string
+
+		if collectionName != "" && strings.HasPrefix(id, collectionName+".") {
+			name = strings.TrimPrefix(id, collectionName+".")
+		} else {
+			collectionName, name, _ = strings.Cut(id, ".")
+		}
+
+		state["id"] = createId(collectionName, name)
+	}
+
+	setDefaults(state)
+
+	upgraded, err := json.Marshal(state)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to encode upgraded state, got error: %s", err))
+		return
+	}
+
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
+
+// calculate a SHA-256 hash of the canonical JSON encoding of a document, which
+// does not depend on the order of its keys or the notation of its numbers
+func documentHash(document map[string]interface{}) (string, error) {
+	jsonBytes, err := json.Marshal(canonicalJsonValue(document))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(jsonBytes)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// rewrite numbers to a canonical notation, so that e.g. 1.0 and 1 or 1e3 and
+// 1000 encode the same way
+func canonicalJsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			result[key] = canonicalJsonValue(element)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, element := range v {
+			result[i] = canonicalJsonValue(element)
+		}
+		return result
+	case json.Number:
+		number, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return v
+		}
+		return convertBigFloatToJsonNumber(number)
+	case float64:
+		return convertBigFloatToJsonNumber(big.NewFloat(v))
+	}
+
+	return value
+}
+
+// report whether two JSON values are equal, regardless of the notation of
+// their numbers
+func jsonValuesEqual(a interface{}, b interface{}) bool {
+	aBytes, aErr := json.Marshal(canonicalJsonValue(a))
+	bBytes, bErr := json.Marshal(canonicalJsonValue(b))
+
+	return aErr == nil && bErr == nil && bytes.Equal(aBytes, bBytes)
+}
+
+// keep only the keys of document which are present in declared
+func projectDocument(document map[string]interface{}, declared map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(declared))
+
+	for key := range declared {
+		if value, ok := document[key]; ok {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+// add the keys which are declared as null but missing in document, the server
+// does not return keys which were cleared
+func keepDeclaredNulls(document map[string]interface{}, declared map[string]interface{}) {
+	for key, value := range declared {
+		if _, ok := document[key]; !ok && value == nil && key != "id" {
+			document[key] = nil
+		}
+	}
+}
+
+// withoutNullValues returns a copy of the document without its top level null
+// values, which the server does not return
+func withoutNullValues(document map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(document))
+
+	for key, value := range document {
+		if value != nil {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+// report whether the value and every value nested in it are known
+func valueFullyKnown(value attr.Value) bool {
+	if value.IsUnknown() {
+		return false
+	}
+
+	if value.IsNull() {
+		return true
+	}
+
+	var nested []attr.Value
+
+	switch v := value.(type) {
+	case basetypes.DynamicValue:
+		if v.UnderlyingValue() == nil {
+			return true
+		}
+
+		return valueFullyKnown(v.UnderlyingValue())
+	case basetypes.ObjectValue:
+		for _, attribute := range v.Attributes() {
+			nested = append(nested, attribute)
+		}
+	case basetypes.MapValue:
+		for _, element := range v.Elements() {
+			nested = append(nested, element)
+		}
+	case basetypes.ListValue:
+		nested = v.Elements()
+	case basetypes.SetValue:
+		nested = v.Elements()
+	case basetypes.TupleValue:
+		nested = v.Elements()
+	}
+
+	for _, element := range nested {
+		if !valueFullyKnown(element) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// convert a terraform value of any type to a JSON compatible value, numbers
+// are converted to json.Number so that they keep their exact value
+func convertTerraformValueToJson(value attr.Value) (interface{}, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("value is unknown")
+	}
+
+	switch v := value.(type) {
+	case basetypes.DynamicValue:
+		return convertTerraformValueToJson(v.UnderlyingValue())
+	case basetypes.StringValue:
+		return v.ValueString(), nil
+	case basetypes.BoolValue:
+		return v.ValueBool(), nil
+	case basetypes.NumberValue:
+		return convertBigFloatToJsonNumber(v.ValueBigFloat()), nil
+	case basetypes.Int64Value:
+		return json.Number(fmt.Sprintf("%d", v.ValueInt64())), nil
+	case basetypes.Float64Value:
+		return convertBigFloatToJsonNumber(big.NewFloat(v.ValueFloat64())), nil
+	case basetypes.ObjectValue:
+		return convertTerraformAttributesToJson(v.Attributes())
+	case basetypes.MapValue:
+		return convertTerraformAttributesToJson(v.Elements())
+	case basetypes.ListValue:
+		return convertTerraformElementsToJson(v.Elements())
+	case basetypes.SetValue:
+		return convertTerraformElementsToJson(v.Elements())
+	case basetypes.TupleValue:
+		return convertTerraformElementsToJson(v.Elements())
+	}
+
+	return nil, fmt.Errorf("unsupported value type %T", value)
+}
+
+func convertTerraformAttributesToJson(attributes map[string]attr.Value) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(attributes))
+
+	for key, value := range attributes {
+		converted, err := convertTerraformValueToJson(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+
+		result[key] = converted
+	}
+
+	return result, nil
+}
+
+func convertTerraformElementsToJson(elements []attr.Value) ([]interface{}, error) {
+	result := make([]interface{}, len(elements))
+
+	for i, value := range elements {
+		converted, err := convertTerraformValueToJson(value)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		result[i] = converted
+	}
+
+	return result, nil
+}
+
+func convertBigFloatToJsonNumber(value *big.Float) json.Number {
+	if value.IsInt() {
+		integer, _ := value.Int(nil)
+		return json.Number(integer.String())
+	}
+
+	return json.Number(value.Text('g', -1))
+}
+
+// convert a JSON value decoded with UseNumber to a terraform value, objects
+// become objects and arrays become tuples like with jsondecode. Null values
+// keep the dynamic type of a null in the configuration
+func convertJsonToTerraformValue(value interface{}) (attr.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return types.DynamicNull(), nil
+	case string:
+		return types.StringValue(v), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case json.Number:
+		number, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, err
+		}
+
+		return types.NumberValue(number), nil
+	case float64:
+		return types.NumberValue(big.NewFloat(v)), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		attributeTypes := make(map[string]attr.Type, len(v))
+		attributes := make(map[string]attr.Value, len(v))
+
+		for _, key := range keys {
+			converted, err := convertJsonToTerraformValue(v[key])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+
+			attributeTypes[key] = converted.Type(context.Background())
+			attributes[key] = converted
+		}
+
+		object, diags := types.ObjectValue(attributeTypes, attributes)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to build object: %v", diags)
+		}
+
+		return object, nil
+	case []interface{}:
+		elementTypes := make([]attr.Type, len(v))
+		elements := make([]attr.Value, len(v))
+
+		for i, element := range v {
+			converted, err := convertJsonToTerraformValue(element)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+
+			elementTypes[i] = converted.Type(context.Background())
+			elements[i] = converted
+		}
+
+		tuple, diags := types.TupleValue(elementTypes, elements)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to build tuple: %v", diags)
+		}
+
+		return tuple, nil
+	}
+
+	return nil, fmt.Errorf("unsupported JSON value %T", value)
 }
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestParseJsonStringToMapKeepsLargeIntegers(t *testing.T) {
+	input := `{"snowflake_id":1234567890123456789,"timestamp_ns":1700000000123456789,"max":9223372036854775807,"price":12.5}`
+
+	document, err := parseJsonStringToMap(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := document["snowflake_id"]; got != json.Number("1234567890123456789") {
+		t.Errorf("snowflake_id = %v, want 1234567890123456789", got)
+	}
+
+	result, err := parseMapToJsonString(document)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := `{"max":9223372036854775807,"price":12.5,"snowflake_id":1234567890123456789,"timestamp_ns":1700000000123456789}`
+
+	if result.ValueString() != want {
+		t.Errorf("round trip = %s, want %s", result.ValueString(), want)
+	}
+}
+
+func TestParseJsonStringToMapRejectsTrailingData(t *testing.T) {
+	if _, err := parseJsonStringToMap(`{"a":1} {"b":2}`); err == nil {
+		t.Error("expected an error for trailing data")
+	}
+}
+
+func TestDocumentHashIgnoresNumberNotation(t *testing.T) {
+	a, err := parseJsonStringToMap(`{"price":1.0,"count":1e3,"id_num":9007199254740993}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	b, err := parseJsonStringToMap(`{"id_num":9007199254740993,"count":1000,"price":1}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	c, err := parseJsonStringToMap(`{"id_num":9007199254740992,"count":1000,"price":1}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	hashA, _ := documentHash(a)
+	hashB, _ := documentHash(b)
+	hashC, _ := documentHash(c)
+
+	if hashA != hashB {
+		t.Errorf("hashes differ for equal documents: %s != %s", hashA, hashB)
+	}
+
+	if hashB == hashC {
+		t.Error("hashes are equal for documents which differ beyond 2^53")
+	}
+}
+
+func TestTerraformValueJsonRoundTrip(t *testing.T) {
+	large, _, _ := big.ParseFloat("1234567890123456789", 10, 512, big.ToNearestEven)
+	price, _, _ := big.ParseFloat("0.1", 10, 512, big.ToNearestEven)
+
+	value := types.ObjectValueMust(
+		map[string]attr.Type{
+			"id_num": types.NumberType,
+			"price":  types.NumberType,
+			"title":  types.StringType,
+			"tags":   types.TupleType{ElemTypes: []attr.Type{types.StringType, types.BoolType}},
+		},
+		map[string]attr.Value{
+			"id_num": types.NumberValue(large),
+			"price":  types.NumberValue(price),
+			"title":  types.StringValue("test"),
+			"tags": types.TupleValueMust(
+				[]attr.Type{types.StringType, types.BoolType},
+				[]attr.Value{types.StringValue("a"), types.BoolValue(true)},
+			),
+		},
+	)
+
+	converted, err := convertTerraformValueToJson(types.DynamicValue(value))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	encoded, err := json.Marshal(converted)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := `{"id_num":1234567890123456789,"price":0.1,"tags":["a",true],"title":"test"}`
+
+	if string(encoded) != want {
+		t.Errorf("encoded = %s, want %s", encoded, want)
+	}
+
+	document, err := parseJsonStringToMap(string(encoded))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	back, err := convertJsonToTerraformValue(document)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !back.Equal(value) {
+		t.Errorf("round trip = %s, want %s", back, value)
+	}
+}
+
+func TestCollectionRelatedIdRoundTrip(t *testing.T) {
+	cases := []struct{ collection, name string }{
+		{"products", "shoe"},
+		{"users", "jane.doe@example.com"},
+		{"catalog.v1.2", "item/with/slashes"},
+	}
+
+	for _, c := range cases {
+		id := createId(c.collection, c.name)
+
+		collection, name, err := splitCollectionRelatedId(id)
+		if err != nil {
+			t.Fatalf("splitCollectionRelatedId(%q): unexpected error: %s", id, err)
+		}
+
+		if collection != c.collection || name != c.name {
+			t.Errorf("splitCollectionRelatedId(%q) = %q, %q, want %q, %q", id, collection, name, c.collection, c.name)
+		}
+	}
+}
+
+func TestSplitCollectionRelatedIdLegacyFormat(t *testing.T) {
+	collection, name, err := splitCollectionRelatedId("products.shoe")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if collection != "products" || name != "shoe" {
+		t.Errorf("got %q, %q, want products, shoe", collection, name)
+	}
+
+	if _, _, err := splitCollectionRelatedId("products.v1.2"); err == nil {
+		t.Error("expected an error for an ambiguous legacy id")
+	}
+}
+
+func TestUpgradeCollectionRelatedIdState(t *testing.T) {
+	req := resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{
+			JSON: []byte(`{"id":"users.jane.doe@example.com","collection_name":"users","name":"jane.doe@example.com","document":"{\"n\":9007199254740993}"}`),
+		},
+	}
+	resp := &resource.UpgradeStateResponse{}
+
+	upgradeDocumentState(context.Background(), req, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	state, err := parseJsonStringToMap(string(resp.DynamicValue.JSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if state["id"] != "users/jane.doe@example.com" {
+		t.Errorf("id = %v, want users/jane.doe@example.com", state["id"])
+	}
+
+	if state["document"] != `{"n":9007199254740993}` {
+		t.Errorf("document = %v, want it unchanged", state["document"])
+	}
+
+	if state["action"] != "create" || state["ownership"] != "full" || state["conflict_policy"] != "overwrite" || state["state_storage"] != "full" {
+		t.Errorf("state = %v, want the schema defaults for attributes added since version 0", state)
+	}
+
+	if state["remove_declared_keys_on_destroy"] != false {
+		t.Errorf("remove_declared_keys_on_destroy = %v, want false", state["remove_declared_keys_on_destroy"])
+	}
+}